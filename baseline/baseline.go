@@ -0,0 +1,140 @@
+// Package baseline makes it possible to suppress known findings, so that only new findings are reported
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+// Finding identifies a single finding of a check on an object
+type Finding struct {
+	Object string `json:"object"`
+	Check  string `json:"check"`
+	Path   string `json:"path"`
+}
+
+type Baseline struct {
+	Findings []Finding `json:"findings"`
+
+	index map[Finding]struct{}
+}
+
+// New creates a baseline of all warnings and critical findings in the scorecard
+func New(scoreCard *scorecard.Scorecard) *Baseline {
+	b := &Baseline{Findings: make([]Finding, 0)}
+	seen := make(map[Finding]struct{})
+
+	for key, object := range *scoreCard {
+		for _, check := range object.Checks {
+			if !isFinding(check) {
+				continue
+			}
+			for _, finding := range findings(key, check) {
+				if _, ok := seen[finding]; ok {
+					continue
+				}
+				seen[finding] = struct{}{}
+				b.Findings = append(b.Findings, finding)
+			}
+		}
+	}
+
+	sort.Slice(b.Findings, func(i, j int) bool {
+		x, y := b.Findings[i], b.Findings[j]
+		if x.Object != y.Object {
+			return x.Object < y.Object
+		}
+		if x.Check != y.Check {
+			return x.Check < y.Check
+		}
+		return x.Path < y.Path
+	})
+
+	b.index = seen
+	return b
+}
+
+// Load reads a baseline that has previously been written with Write
+func Load(r io.Reader) (*Baseline, error) {
+	var b Baseline
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, fmt.Errorf("failed to decode baseline: %w", err)
+	}
+
+	b.index = make(map[Finding]struct{}, len(b.Findings))
+	for _, f := range b.Findings {
+		b.index[f] = struct{}{}
+	}
+	return &b, nil
+}
+
+func (b *Baseline) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	return enc.Encode(b)
+}
+
+// Contains returns true if the finding is a part of the baseline
+func (b *Baseline) Contains(f Finding) bool {
+	_, ok := b.index[f]
+	return ok
+}
+
+// Apply removes all findings that are a part of the baseline from the scorecard.
+// Checks where all findings are a part of the baseline are marked as skipped.
+func (b *Baseline) Apply(scoreCard *scorecard.Scorecard) {
+	for key, object := range *scoreCard {
+		for i, check := range object.Checks {
+			if !isFinding(check) {
+				continue
+			}
+
+			// Checks without comments are identified by an empty path
+			if len(check.Comments) == 0 {
+				if b.Contains(Finding{Object: key, Check: check.Check.ID}) {
+					object.Checks[i] = skipped(check)
+				}
+				continue
+			}
+
+			var newComments []scorecard.TestScoreComment
+			for _, comment := range check.Comments {
+				if !b.Contains(Finding{Object: key, Check: check.Check.ID, Path: comment.Path}) {
+					newComments = append(newComments, comment)
+				}
+			}
+
+			if len(newComments) == 0 {
+				object.Checks[i] = skipped(check)
+				continue
+			}
+			object.Checks[i].Comments = newComments
+		}
+	}
+}
+
+func isFinding(check scorecard.TestScore) bool {
+	return !check.Skipped && check.Grade <= scorecard.GradeWarning
+}
+
+func findings(key string, check scorecard.TestScore) []Finding {
+	if len(check.Comments) == 0 {
+		return []Finding{{Object: key, Check: check.Check.ID}}
+	}
+
+	var res []Finding
+	for _, comment := range check.Comments {
+		res = append(res, Finding{Object: key, Check: check.Check.ID, Path: comment.Path})
+	}
+	return res
+}
+
+func skipped(check scorecard.TestScore) scorecard.TestScore {
+	check.Skipped = true
+	check.Comments = []scorecard.TestScoreComment{{Summary: "Skipped because all findings are in the baseline"}}
+	return check
+}
@@ -0,0 +1,78 @@
+package baseline
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+func testScorecard() *scorecard.Scorecard {
+	return &scorecard.Scorecard{
+		"Deployment/apps/v1/default/foo": &scorecard.ScoredObject{
+			Checks: []scorecard.TestScore{
+				{
+					Check: ks.Check{ID: "container-resources"},
+					Grade: scorecard.GradeCritical,
+					Comments: []scorecard.TestScoreComment{
+						{Path: "app", Summary: "CPU limit is not set"},
+						{Path: "sidecar", Summary: "CPU limit is not set"},
+					},
+				},
+				{
+					Check: ks.Check{ID: "pod-networkpolicy"},
+					Grade: scorecard.GradeWarning,
+				},
+				{
+					Check: ks.Check{ID: "stable-version"},
+					Grade: scorecard.GradeAllOK,
+				},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	b := New(testScorecard())
+	assert.Equal(t, []Finding{
+		{Object: "Deployment/apps/v1/default/foo", Check: "container-resources", Path: "app"},
+		{Object: "Deployment/apps/v1/default/foo", Check: "container-resources", Path: "sidecar"},
+		{Object: "Deployment/apps/v1/default/foo", Check: "pod-networkpolicy"},
+	}, b.Findings)
+}
+
+func TestWriteAndLoad(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, New(testScorecard()).Write(&buf))
+
+	b, err := Load(&buf)
+	assert.NoError(t, err)
+	assert.True(t, b.Contains(Finding{Object: "Deployment/apps/v1/default/foo", Check: "pod-networkpolicy"}))
+	assert.False(t, b.Contains(Finding{Object: "Deployment/apps/v1/default/bar", Check: "pod-networkpolicy"}))
+}
+
+func TestApply(t *testing.T) {
+	b := &Baseline{index: map[Finding]struct{}{
+		{Object: "Deployment/apps/v1/default/foo", Check: "container-resources", Path: "app"}: {},
+		{Object: "Deployment/apps/v1/default/foo", Check: "pod-networkpolicy"}:                {},
+	}}
+
+	sc := testScorecard()
+	assert.True(t, sc.AnyBelowOrEqualToGrade(scorecard.GradeCritical))
+
+	b.Apply(sc)
+	checks := (*sc)["Deployment/apps/v1/default/foo"].Checks
+
+	// Only the new finding is left
+	assert.False(t, checks[0].Skipped)
+	assert.Equal(t, []scorecard.TestScoreComment{{Path: "sidecar", Summary: "CPU limit is not set"}}, checks[0].Comments)
+
+	// All findings are in the baseline
+	assert.True(t, checks[1].Skipped)
+	assert.False(t, checks[2].Skipped)
+
+	assert.True(t, sc.AnyBelowOrEqualToGrade(scorecard.GradeCritical))
+}
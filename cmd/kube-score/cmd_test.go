@@ -1,9 +1,17 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	flag "github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/parser"
 )
 
 func TestParseCli(t *testing.T) {
@@ -38,3 +46,74 @@ func TestParseCli(t *testing.T) {
 	assert.Equal(t, 2, offset)
 	assert.Nil(t, err)
 }
+
+func TestApplyConfigFile(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), ".kube-score.yaml")
+	err := os.WriteFile(configFile, []byte(`
+kubernetes-version: v1.25
+ignore-test: [pod-networkpolicy]
+exit-one-on-warning: true
+`), 0o644)
+	assert.NoError(t, err)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	kubernetesVersion := fs.String("kubernetes-version", "v1.18", "")
+	ignoreTests := fs.StringSlice("ignore-test", []string{}, "")
+	exitOneOnWarning := fs.Bool("exit-one-on-warning", false, "")
+
+	assert.NoError(t, fs.Parse([]string{"--kubernetes-version", "v1.27"}))
+	_, err = applyConfigFile(fs, configFile)
+	assert.NoError(t, err)
+
+	// Explicitly set flags take precedence over the file
+	assert.Equal(t, "v1.27", *kubernetesVersion)
+	assert.Equal(t, []string{"pod-networkpolicy"}, *ignoreTests)
+	assert.True(t, *exitOneOnWarning)
+}
+
+func TestUnknownKindsSummary(t *testing.T) {
+	p, err := parser.New()
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles(config.Configuration{
+		AllFiles: []ks.NamedReader{namedReader{Reader: strings.NewReader(`
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: a
+---
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: a
+---
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: b
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: a
+`), name: "test.yaml"}},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, `Only the metadata of 3 objects was checked, as their kinds are not supported by kube-score:
+    2 Certificate (cert-manager.io/v1)
+    1 ServiceMonitor (monitoring.coreos.com/v1)
+`, unknownKindsSummary(parsed.UnknownObjects()))
+
+	assert.Equal(t, "", unknownKindsSummary(nil))
+}
+
+func TestUnknownSeverityChecks(t *testing.T) {
+	assert.Equal(t, []string{"container-image-tags", "no-such-check"}, unknownSeverityChecks(map[string]config.Severity{
+		"container-image-tag":  config.SeverityInfo,
+		"parse-error":          config.SeverityWarning,
+		"no-such-check":        config.SeverityInfo,
+		"container-image-tags": config.SeverityCritical,
+	}))
+
+	assert.Empty(t, unknownSeverityChecks(nil))
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreFileName is the name of the file that lists patterns of files to skip when a directory is scored
+const ignoreFileName = ".kube-scoreignore"
+
+// File extensions that are read when a directory is scored
+var manifestExtensions = map[string]struct{}{
+	".yaml": {},
+	".yml":  {},
+	".json": {},
+}
+
+// fileFilter decides which files are read when directories, or glob patterns, are given as input
+type fileFilter struct {
+	include []string
+	exclude []string
+}
+
+// findFiles expands all arguments to a list of files to read.
+//
+// Regular files are always read. Directories are walked recursively, and all YAML and JSON files in them are read.
+// Arguments containing glob patterns (including "**") are matched against the files in the directory that precedes
+// the first pattern. Discovered files can be filtered with the include and exclude patterns,
+// and by a .kube-scoreignore file.
+func findFiles(args []string, filter fileFilter) ([]string, error) {
+	var res []string
+
+	for _, arg := range args {
+		if arg == "-" {
+			res = append(res, arg)
+			continue
+		}
+
+		if isGlob(arg) {
+			root, pattern := splitGlob(arg)
+			files, err := walk(root, func(rel string) bool {
+				return matchGlob(pattern, rel)
+			}, filter)
+			if err != nil {
+				return nil, err
+			}
+			if len(files) == 0 {
+				return nil, fmt.Errorf("no files matched %s", arg)
+			}
+			res = append(res, files...)
+			continue
+		}
+
+		stat, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		if !stat.IsDir() {
+			res = append(res, arg)
+			continue
+		}
+
+		files, err := walk(arg, func(rel string) bool {
+			_, ok := manifestExtensions[strings.ToLower(path.Ext(rel))]
+			return ok
+		}, filter)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, files...)
+	}
+
+	return res, nil
+}
+
+// walk returns all files in root that are selected by the selector and the filter.
+// The selector and the filter patterns are given paths relative to root, separated with forward slashes.
+func walk(root string, selector func(rel string) bool, filter fileFilter) ([]string, error) {
+	var res []string
+	var ignores []ignoreFile
+
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if rel != "." && (d.Name() == ".git" || isIgnored(ignores, rel, true) || matchAny(filter.exclude, rel)) {
+				return filepath.SkipDir
+			}
+
+			ignore, err := readIgnoreFile(filepath.Join(p, ignoreFileName), rel)
+			if err != nil {
+				return err
+			}
+			if ignore != nil {
+				ignores = append(ignores, *ignore)
+			}
+			return nil
+		}
+
+		if !selector(rel) {
+			return nil
+		}
+		if len(filter.include) > 0 && !matchAny(filter.include, rel) {
+			return nil
+		}
+		if matchAny(filter.exclude, rel) || isIgnored(ignores, rel, false) {
+			return nil
+		}
+
+		res = append(res, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+type ignorePattern struct {
+	pattern string
+	negate  bool
+	dirOnly bool
+}
+
+// ignoreFile is a parsed .kube-scoreignore file. The syntax is a subset of the .gitignore syntax.
+type ignoreFile struct {
+	// dir is the directory of the ignore file, relative to the root of the walk
+	dir      string
+	patterns []ignorePattern
+}
+
+func readIgnoreFile(fileName, dir string) (*ignoreFile, error) {
+	fp, err := os.Open(fileName)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	res := &ignoreFile{dir: dir}
+
+	scanner := bufio.NewScanner(fp)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var p ignorePattern
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+
+		// Patterns without a slash match at any depth, patterns with a slash are relative to the ignore file
+		if strings.Contains(line, "/") {
+			p.pattern = strings.TrimPrefix(line, "/")
+		} else {
+			p.pattern = "**/" + line
+		}
+
+		res.patterns = append(res.patterns, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", fileName, err)
+	}
+
+	return res, nil
+}
+
+// isIgnored returns true if the path is ignored by any of the ignore files. The last matching pattern wins.
+func isIgnored(ignores []ignoreFile, rel string, isDir bool) bool {
+	ignored := false
+	for _, ignore := range ignores {
+		relToIgnore := rel
+		if ignore.dir != "." {
+			if !strings.HasPrefix(rel, ignore.dir+"/") {
+				continue
+			}
+			relToIgnore = strings.TrimPrefix(rel, ignore.dir+"/")
+		}
+
+		for _, p := range ignore.patterns {
+			if p.dirOnly && !isDir {
+				continue
+			}
+			if matchGlob(p.pattern, relToIgnore) {
+				ignored = !p.negate
+			}
+		}
+	}
+	return ignored
+}
+
+func matchAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		// Patterns without a slash are matched against the file name
+		if !strings.Contains(p, "/") {
+			p = "**/" + p
+		}
+		if matchGlob(p, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+func isGlob(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// splitGlob splits a path containing a glob pattern into the directory preceding the first pattern, and the pattern
+func splitGlob(s string) (root, pattern string) {
+	parts := strings.Split(filepath.ToSlash(s), "/")
+	for i, part := range parts {
+		if isGlob(part) {
+			root = strings.Join(parts[:i], "/")
+			if root == "" {
+				root = "."
+				if i > 0 {
+					root = "/"
+				}
+			}
+			return filepath.FromSlash(root), strings.Join(parts[i:], "/")
+		}
+	}
+	return filepath.FromSlash(s), ""
+}
+
+// matchGlob matches a forward slash separated path against a pattern.
+// The pattern supports the syntax of path.Match, and "**" matches zero or more directories.
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Trailing "**" matches everything
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+
+	return len(name) == 0
+}
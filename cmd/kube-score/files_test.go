@@ -0,0 +1,119 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		assert.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+}
+
+func relativeFiles(t *testing.T, root string, files []string) []string {
+	var res []string
+	for _, f := range files {
+		rel, err := filepath.Rel(root, f)
+		assert.NoError(t, err)
+		res = append(res, filepath.ToSlash(rel))
+	}
+	return res
+}
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"*.yaml", "a.yaml", true},
+		{"*.yaml", "dir/a.yaml", false},
+		{"**/*.yaml", "a.yaml", true},
+		{"**/*.yaml", "dir/sub/a.yaml", true},
+		{"dir/**", "dir/sub/a.yaml", true},
+		{"dir/**/a.yaml", "dir/a.yaml", true},
+		{"dir/**/a.yaml", "other/a.yaml", false},
+		{"dir/*/a.yaml", "dir/sub/deeper/a.yaml", false},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, matchGlob(tc.pattern, tc.name), "%s %s", tc.pattern, tc.name)
+	}
+}
+
+func TestFindFilesDirectory(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"deployment.yaml":              "",
+		"service.yml":                  "",
+		"list.json":                    "",
+		"README.md":                    "",
+		"nested/deep/statefulset.yaml": "",
+		"charts/values.yaml":           "",
+		"generated/skip.yaml":          "",
+		"generated/keep.yaml":          "",
+		".kube-scoreignore":            "# Not manifests\ncharts/\ngenerated/*\n!generated/keep.yaml\n",
+	})
+
+	files, err := findFiles([]string{root}, fileFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"deployment.yaml",
+		"generated/keep.yaml",
+		"list.json",
+		"nested/deep/statefulset.yaml",
+		"service.yml",
+	}, relativeFiles(t, root, files))
+}
+
+func TestFindFilesIncludeExclude(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"a/deployment.yaml": "",
+		"a/test.yaml":       "",
+		"b/service.yaml":    "",
+		"b/list.json":       "",
+	})
+
+	files, err := findFiles([]string{root}, fileFilter{
+		include: []string{"*.yaml"},
+		exclude: []string{"test.yaml", "b"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a/deployment.yaml"}, relativeFiles(t, root, files))
+}
+
+func TestFindFilesGlob(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"a/deployment.yaml":     "",
+		"a/b/c/service.yaml":    "",
+		"a/b/c/not-yaml.txt":    "",
+		"other/deployment.yaml": "",
+	})
+
+	files, err := findFiles([]string{filepath.Join(root, "a", "**", "*.yaml")}, fileFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a/b/c/service.yaml", "a/deployment.yaml"}, relativeFiles(t, filepath.Join(root), files))
+
+	_, err = findFiles([]string{filepath.Join(root, "*.nothing")}, fileFilter{})
+	assert.Error(t, err)
+}
+
+func TestFindFilesExplicit(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"manifest.txt": "",
+	})
+
+	// Explicitly listed files are always read
+	files, err := findFiles([]string{"-", filepath.Join(root, "manifest.txt")}, fileFilter{exclude: []string{"*.txt"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"-", filepath.Join(root, "manifest.txt")}, files)
+}
@@ -10,13 +10,18 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/mattn/go-isatty"
 	flag "github.com/spf13/pflag"
 	"golang.org/x/term"
 
+	"github.com/younes-bami/kube-score/baseline"
 	"github.com/younes-bami/kube-score/config"
 	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/helm"
+	"github.com/younes-bami/kube-score/kustomize"
 	"github.com/younes-bami/kube-score/parser"
 	"github.com/younes-bami/kube-score/renderer/ci"
 	"github.com/younes-bami/kube-score/renderer/human"
@@ -47,6 +52,13 @@ func main() {
 			}
 		},
 
+		"baseline": func(helpName string, args []string) {
+			if err := createBaseline(helpName, args); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to create baseline: %v\n", err)
+				os.Exit(1)
+			}
+		},
+
 		"version": func(helpName string, args []string) {
 			cmdVersion()
 		},
@@ -79,6 +91,7 @@ func setDefault(fs *flag.FlagSet, binName, actionName string, displayForMoreInfo
 Actions:
 	score	Checks all files in the input, and gives them a score and recommendations
 	list	Prints a CSV list of all available score checks
+	baseline	Writes all current findings to a baseline file, to be used with "score --baseline"
 	version	Print the version of kube-score
 	help	Print this message`+"\n\n", binName, binName)
 
@@ -98,55 +111,75 @@ Actions:
 	}
 }
 
-func scoreFiles(binName string, args []string) error {
-	fs := flag.NewFlagSet(binName, flag.ExitOnError)
-	exitOneOnWarning := fs.Bool("exit-one-on-warning", false, "Exit with code 1 in case of warnings")
-	ignoreContainerCpuLimit := fs.Bool("ignore-container-cpu-limit", false, "Disables the requirement of setting a container CPU limit")
-	ignoreContainerMemoryLimit := fs.Bool("ignore-container-memory-limit", false, "Disables the requirement of setting a container memory limit")
-	verboseOutput := fs.CountP("verbose", "v", "Enable verbose output, can be set multiple times for increased verbosity.")
-	printHelp := fs.Bool("help", false, "Print help")
-	outputFormat := fs.StringP("output-format", "o", "human", "Set to 'human', 'json', 'ci' or 'sarif'. If set to ci, kube-score will output the program in a format that is easier to parse by other programs. Sarif output allows for easier integration with CI platforms.")
-	outputVersion := fs.String("output-version", "", "Changes the version of the --output-format. The 'json' format has version 'v2' (default) and 'v1' (deprecated, will be removed in v1.7.0). The 'human' and 'ci' formats has only version 'v1' (default). If not explicitly set, the default version for that particular output format will be used.")
-	color := fs.String("color", "auto", "If the output should be colored. Set to 'always', 'never' or 'auto'. If set to 'auto', kube-score will try to detect if the current terminal / platform supports colors. If set to 'never', kube-score will not output any colors. If set to 'always', kube-score will output colors even if the current terminal / platform does not support colors.")
-	optionalTests := fs.StringSlice("enable-optional-test", []string{}, "Enable an optional test, can be set multiple times")
-	ignoreTests := fs.StringSlice("ignore-test", []string{}, "Disable a test, can be set multiple times")
-	disableIgnoreChecksAnnotation := fs.Bool("disable-ignore-checks-annotations", false, "Set to true to disable the effect of the 'kube-score/ignore' annotations")
-	disableOptionalChecksAnnotation := fs.Bool("disable-optional-checks-annotations", false, "Set to true to disable the effect of the 'kube-score/enable' annotations")
-	kubernetesVersion := fs.String("kubernetes-version", "v1.18", "Setting the kubernetes-version will affect the checks ran against the manifests. Set this to the version of Kubernetes that you're using in production for the best results.")
-	setDefault(fs, binName, "score", false)
+// inputFlags are the flags that control how the input is read and scored. They are shared by all commands that score files.
+type inputFlags struct {
+	ignoreContainerCpuLimit         *bool
+	ignoreContainerMemoryLimit      *bool
+	verboseOutput                   *int
+	optionalTests                   *[]string
+	ignoreTests                     *[]string
+	disableIgnoreChecksAnnotation   *bool
+	disableOptionalChecksAnnotation *bool
+	kubernetesVersion               *string
+	configFile                      *string
+	liveObjects                     *bool
+	include                         *[]string
+	exclude                         *[]string
+	kustomize                       *[]string
+	helmChart                       *string
+	helmValues                      *[]string
+	helmSet                         *[]string
+	helmReleaseName                 *string
+	tolerateParseErrors             *bool
+	podSecurityLevel                *string
+	allowCapabilities               *[]string
+	allowImageRegistries            *[]string
+	requireImageDigest              *bool
+	forbidImageTags                 *[]string
+}
 
-	err := fs.Parse(args)
-	if err != nil {
-		return fmt.Errorf("failed to parse files: %w", err)
+func registerInputFlags(fs *flag.FlagSet) *inputFlags {
+	return &inputFlags{
+		ignoreContainerCpuLimit:         fs.Bool("ignore-container-cpu-limit", false, "Disables the requirement of setting a container CPU limit"),
+		ignoreContainerMemoryLimit:      fs.Bool("ignore-container-memory-limit", false, "Disables the requirement of setting a container memory limit"),
+		verboseOutput:                   fs.CountP("verbose", "v", "Enable verbose output, can be set multiple times for increased verbosity."),
+		optionalTests:                   fs.StringSlice("enable-optional-test", []string{}, "Enable an optional test, can be set multiple times"),
+		ignoreTests:                     fs.StringSlice("ignore-test", []string{}, "Disable a test, can be set multiple times"),
+		disableIgnoreChecksAnnotation:   fs.Bool("disable-ignore-checks-annotations", false, "Set to true to disable the effect of the 'kube-score/ignore' annotations"),
+		disableOptionalChecksAnnotation: fs.Bool("disable-optional-checks-annotations", false, "Set to true to disable the effect of the 'kube-score/enable' annotations"),
+		kubernetesVersion:               fs.String("kubernetes-version", "v1.18", "Setting the kubernetes-version will affect the checks ran against the manifests. Set this to the version of Kubernetes that you're using in production for the best results."),
+		configFile:                      fs.String("config", "", "Path to a kube-score configuration file. If not set, kube-score will look for a "+config.FileName+" file in the current directory and its parents. Flags that are explicitly set take precedence over the values in the file."),
+		liveObjects:                     fs.Bool("live-objects", false, "Set to true if the input has been exported from a running cluster, for example with 'kubectl get all,pdb,netpol,hpa,ingress -A -o json'. Fields that are populated by the server, such as status and managedFields, are removed before scoring, and objects that are managed by a controller (such as the Pods of a ReplicaSet) are not scored."),
+		include:                         fs.StringSlice("include", []string{}, "Only read files matching this glob pattern when a directory is given as input, can be set multiple times. Patterns without a slash are matched against the file name, and '**' matches any number of directories."),
+		exclude:                         fs.StringSlice("exclude", []string{}, "Do not read files or directories matching this glob pattern when a directory is given as input, can be set multiple times."),
+		kustomize:                       fs.StringSlice("kustomize", []string{}, "Render the kustomization in this directory, and score the result. Can be set multiple times, and can be combined with files given as arguments."),
+		helmChart:                       fs.String("helm-chart", "", "Render the Helm chart in this directory, and score the result. Findings are reported with the template file and line that the object was defined on."),
+		helmValues:                      fs.StringSlice("values", []string{}, "Values file to use when rendering the --helm-chart, can be set multiple times. Later files take precedence."),
+		helmSet:                         fs.StringArray("set", []string{}, "Set a value when rendering the --helm-chart, on the format 'key1=val1,key2=val2'. Can be set multiple times, and takes precedence over --values."),
+		helmReleaseName:                 fs.String("helm-release-name", "release-name", "The release name to use when rendering the --helm-chart"),
+		tolerateParseErrors:             fs.Bool("tolerate-parse-errors", false, "Set to true to continue when a document fails to parse. The document is reported as a critical 'parse-error' finding, and all other objects are scored as usual."),
+		allowCapabilities:               fs.StringSlice("allow-capability", config.DefaultAllowedCapabilities, "Allow containers to add this Linux capability in the optional container-security-context-capabilities check, can be set multiple times. Setting this flag replaces the default list."),
+		allowImageRegistries:            fs.StringSlice("allow-image-registry", []string{}, "Only allow images from this registry, or repository prefix such as 'registry.example.com/team', can be set multiple times. Images from Docker Hub are in the 'docker.io' registry."),
+		requireImageDigest:              fs.Bool("require-image-digest", false, "Set to true to require that all images are pinned by a sha256 digest"),
+		forbidImageTags:                 fs.StringSlice("forbid-image-tag", []string{}, "Do not allow images with a tag matching this regular expression, such as 'latest' or '.*-SNAPSHOT', can be set multiple times. The expression must match the whole tag."),
+		podSecurityLevel:                fs.String("pod-security-level", "", "Evaluate all pods against a profile of the Pod Security Standards. Set to 'baseline' or 'restricted'. Each violated control is reported by the pod-security-standards check."),
 	}
+}
 
-	if *printHelp {
-		fs.Usage()
-		return nil
-	}
+// configuration creates the configuration from the flags, the configuration file and the files given as arguments.
+// The configuration file must have been applied to the flags with applyConfigFile before calling configuration.
+func (f *inputFlags) configuration(fs *flag.FlagSet, binName, actionName string, file *config.File) (config.Configuration, error) {
+	if len(fs.Args()) == 0 && len(*f.kustomize) == 0 && *f.helmChart == "" {
+		return config.Configuration{}, fmt.Errorf(`Error: No files given as arguments.
 
-	if *outputFormat != "human" && *outputFormat != "ci" && *outputFormat != "json" && *outputFormat != "sarif" {
-		fs.Usage()
-		return fmt.Errorf("Error: --output-format must be set to: 'human', 'json', 'sarif' or 'ci'")
-	}
+Usage: %s %s [--flag1 --flag2] file1 file2 ...
 
-	acceptedColors := map[string]bool{
-		"auto":   true,
-		"always": true,
-		"never":  true,
-	}
-	if !acceptedColors[*color] {
-		fs.Usage()
-		return fmt.Errorf("Error: --color must be set to: 'auto', 'always' or 'never'")
+Use "-" as filename to read from STDIN. Directories are searched recursively for YAML and JSON files.`, execName(binName), actionName)
 	}
 
-	filesToRead := fs.Args()
-	if len(filesToRead) == 0 {
-		return fmt.Errorf(`Error: No files given as arguments.
-
-Usage: %s score [--flag1 --flag2] file1 file2 ...
-
-Use "-" as filename to read from STDIN.`, execName(binName))
+	filesToRead, err := findFiles(fs.Args(), fileFilter{include: *f.include, exclude: *f.exclude})
+	if err != nil {
+		return config.Configuration{}, err
 	}
 
 	var allFilePointers []ks.NamedReader
@@ -162,48 +195,205 @@ Use "-" as filename to read from STDIN.`, execName(binName))
 			var err error
 			fp, err = os.Open(file)
 			if err != nil {
-				return err
+				return config.Configuration{}, err
 			}
 			filename, _ = filepath.Abs(file)
 		}
 		allFilePointers = append(allFilePointers, namedReader{Reader: fp, name: filename})
 	}
 
-	ignoredTests := listToStructMap(ignoreTests)
-	enabledOptionalTests := listToStructMap(optionalTests)
+	for _, dir := range *f.kustomize {
+		rendered, err := kustomize.Build(dir)
+		if err != nil {
+			return config.Configuration{}, fmt.Errorf("failed to build kustomization %s: %w", dir, err)
+		}
+		allFilePointers = append(allFilePointers, rendered...)
+	}
+
+	if *f.helmChart != "" {
+		rendered, err := helm.Render(*f.helmChart, helm.Options{
+			ReleaseName: *f.helmReleaseName,
+			ValuesFiles: *f.helmValues,
+			Set:         *f.helmSet,
+			KubeVersion: *f.kubernetesVersion,
+		})
+		if err != nil {
+			return config.Configuration{}, fmt.Errorf("failed to render Helm chart %s: %w", *f.helmChart, err)
+		}
+		allFilePointers = append(allFilePointers, rendered...)
+	}
+
+	ignoredTests := listToStructMap(f.ignoreTests)
+	enabledOptionalTests := listToStructMap(f.optionalTests)
 
-	kubeVer, err := config.ParseSemver(*kubernetesVersion)
+	kubeVer, err := config.ParseSemver(*f.kubernetesVersion)
 	if err != nil {
-		return errors.New("Invalid --kubernetes-version. Use on format \"vN.NN\"")
+		return config.Configuration{}, errors.New("Invalid --kubernetes-version. Use on format \"vN.NN\"")
 	}
 
-	cnf := config.Configuration{
+	podSecurityLevel := config.PodSecurityLevel(*f.podSecurityLevel)
+	if podSecurityLevel != "" {
+		if !podSecurityLevel.Valid() {
+			return config.Configuration{}, errors.New("Invalid --pod-security-level. Set to 'baseline' or 'restricted'")
+		}
+		enabledOptionalTests["pod-security-standards"] = struct{}{}
+	}
+
+	// Fields with the wrong type can only be reported when the check is enabled for all objects, as the annotations
+	// of an object are not known before it has been decoded
+	_, strictDecoding := enabledOptionalTests["strict-decoding"]
+
+	forbiddenImageTags, err := config.ParseImageTagPatterns(*f.forbidImageTags)
+	if err != nil {
+		return config.Configuration{}, fmt.Errorf("Invalid --forbid-image-tag: %w", err)
+	}
+
+	return config.Configuration{
 		AllFiles:                              allFilePointers,
-		VerboseOutput:                         *verboseOutput,
-		IgnoreContainerCpuLimitRequirement:    *ignoreContainerCpuLimit,
-		IgnoreContainerMemoryLimitRequirement: *ignoreContainerMemoryLimit,
+		VerboseOutput:                         *f.verboseOutput,
+		IgnoreContainerCpuLimitRequirement:    *f.ignoreContainerCpuLimit,
+		IgnoreContainerMemoryLimitRequirement: *f.ignoreContainerMemoryLimit,
 		IgnoredTests:                          ignoredTests,
 		EnabledOptionalTests:                  enabledOptionalTests,
-		UseIgnoreChecksAnnotation:             !*disableIgnoreChecksAnnotation,
-		UseOptionalChecksAnnotation:           !*disableOptionalChecksAnnotation,
+		UseIgnoreChecksAnnotation:             !*f.disableIgnoreChecksAnnotation,
+		UseOptionalChecksAnnotation:           !*f.disableOptionalChecksAnnotation,
 		KubernetesVersion:                     kubeVer,
-	}
+		SeverityOverrides:                     file.Severity,
+		LiveObjects:                           *f.liveObjects,
+		TolerateParseErrors:                   *f.tolerateParseErrors,
+		StrictDecoding:                        strictDecoding,
+		PodTemplates:                          file.PodTemplates,
+		HostPathAllowlist:                     file.HostPathAllowlist,
+		ResourceBounds:                        file.ResourceBounds,
+		PodSecurityLevel:                      podSecurityLevel,
+		AllowedCapabilities:                   *f.allowCapabilities,
+		AllowedImageRegistries:                *f.allowImageRegistries,
+		RequireImageDigest:                    *f.requireImageDigest,
+		ForbiddenImageTags:                    forbiddenImageTags,
+	}, nil
+}
 
+// scoreConfiguration parses and scores all files in the configuration
+func scoreConfiguration(cnf config.Configuration) (*scorecard.Scorecard, error) {
 	p, err := parser.New()
 	if err != nil {
-		return fmt.Errorf("failed to initializer parser: %w", err)
+		return nil, fmt.Errorf("failed to initializer parser: %w", err)
 	}
 
 	parsedFiles, err := p.ParseFiles(cnf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse files: %w", err)
+	}
+
+	if cnf.VerboseOutput > 0 {
+		if summary := unknownKindsSummary(parsedFiles.UnknownObjects()); summary != "" {
+			_, _ = fmt.Fprint(os.Stderr, summary)
+		}
+	}
+
+	return score.Score(parsedFiles, cnf)
+}
+
+// unknownKindsSummary describes how many objects of each kind that only had their metadata checked,
+// as kube-score has no specific support for the kind. An empty string is returned if there are no such objects.
+func unknownKindsSummary(objects []ks.UnknownObject) string {
+	if len(objects) == 0 {
+		return ""
+	}
+
+	counts := make(map[string]int)
+	for _, o := range objects {
+		typeMeta := o.GetTypeMeta()
+		counts[fmt.Sprintf("%s (%s)", typeMeta.Kind, typeMeta.APIVersion)]++
+	}
+
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		if counts[kinds[i]] != counts[kinds[j]] {
+			return counts[kinds[i]] > counts[kinds[j]]
+		}
+		return kinds[i] < kinds[j]
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Only the metadata of %d objects was checked, as their kinds are not supported by kube-score:\n", len(objects))
+	for _, kind := range kinds {
+		fmt.Fprintf(&b, "    %d %s\n", counts[kind], kind)
+	}
+	return b.String()
+}
+
+func scoreFiles(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ExitOnError)
+	exitOneOnWarning := fs.Bool("exit-one-on-warning", false, "Exit with code 1 in case of warnings")
+	printHelp := fs.Bool("help", false, "Print help")
+	outputFormat := fs.StringP("output-format", "o", "human", "Set to 'human', 'json', 'ci' or 'sarif'. If set to ci, kube-score will output the program in a format that is easier to parse by other programs. Sarif output allows for easier integration with CI platforms.")
+	outputVersion := fs.String("output-version", "", "Changes the version of the --output-format. The 'json' format has version 'v2' (default) and 'v1' (deprecated, will be removed in v1.7.0). The 'human' and 'ci' formats has only version 'v1' (default). If not explicitly set, the default version for that particular output format will be used.")
+	color := fs.String("color", "auto", "If the output should be colored. Set to 'always', 'never' or 'auto'. If set to 'auto', kube-score will try to detect if the current terminal / platform supports colors. If set to 'never', kube-score will not output any colors. If set to 'always', kube-score will output colors even if the current terminal / platform does not support colors.")
+	baselineFile := fs.String("baseline", "", "Path to a baseline file created with the baseline command. Findings that are in the baseline are not reported, and do not affect the exit code.")
+	input := registerInputFlags(fs)
+	setDefault(fs, binName, "score", false)
+
+	err := fs.Parse(args)
 	if err != nil {
 		return fmt.Errorf("failed to parse files: %w", err)
 	}
 
-	scoreCard, err := score.Score(parsedFiles, cnf)
+	configuration, err := applyConfigFile(fs, *input.configFile)
+	if err != nil {
+		return err
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	if *outputFormat != "human" && *outputFormat != "ci" && *outputFormat != "json" && *outputFormat != "sarif" {
+		fs.Usage()
+		return fmt.Errorf("Error: --output-format must be set to: 'human', 'json', 'sarif' or 'ci'")
+	}
+
+	acceptedColors := map[string]bool{
+		"auto":   true,
+		"always": true,
+		"never":  true,
+	}
+	if !acceptedColors[*color] {
+		fs.Usage()
+		return fmt.Errorf("Error: --color must be set to: 'auto', 'always' or 'never'")
+	}
+
+	cnf, err := input.configuration(fs, binName, "score", configuration)
+	if err != nil {
+		return err
+	}
+
+	var knownFindings *baseline.Baseline
+	if *baselineFile != "" {
+		fp, err := os.Open(*baselineFile)
+		if err != nil {
+			return fmt.Errorf("failed to open baseline: %w", err)
+		}
+		knownFindings, err = baseline.Load(fp)
+		fp.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	scoreCard, err := scoreConfiguration(cnf)
 	if err != nil {
 		return err
 	}
 
+	if knownFindings != nil {
+		knownFindings.Apply(scoreCard)
+	}
+
 	var exitCode int
 	switch {
 	case scoreCard.AnyBelowOrEqualToGrade(scorecard.GradeCritical):
@@ -232,7 +422,7 @@ Use "-" as filename to read from STDIN.`, execName(binName))
 		if err != nil {
 			termWidth = 80
 		}
-		r, err = human.Human(scoreCard, *verboseOutput, termWidth, useColor(*color))
+		r, err = human.Human(scoreCard, cnf.VerboseOutput, termWidth, useColor(*color))
 		if err != nil {
 			return err
 		}
@@ -250,6 +440,110 @@ Use "-" as filename to read from STDIN.`, execName(binName))
 	return nil
 }
 
+// applyConfigFile loads the configuration file, and uses its values for all flags that have not been explicitly set.
+// The loaded file is returned, or an empty configuration if no file was found.
+func applyConfigFile(fs *flag.FlagSet, configFile string) (*config.File, error) {
+	if configFile == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get working directory: %w", err)
+		}
+		configFile, err = config.FindFile(wd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find configuration file: %w", err)
+		}
+		if configFile == "" {
+			return &config.File{}, nil
+		}
+	}
+
+	file, err := config.LoadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration file: %w", err)
+	}
+
+	if unknown := unknownSeverityChecks(file.Severity); len(unknown) > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "The severity is overridden for checks that do not exist in %s: %s\n", configFile, strings.Join(unknown, ", "))
+	}
+
+	for name, values := range file.Flags() {
+		// Not all commands support all flags
+		if fs.Lookup(name) == nil || fs.Changed(name) {
+			continue
+		}
+		for _, value := range values {
+			if err := fs.Set(name, value); err != nil {
+				return nil, fmt.Errorf("invalid value for %s in %s: %w", name, configFile, err)
+			}
+		}
+	}
+
+	return file, nil
+}
+
+// unknownSeverityChecks returns the sorted IDs of the severity overrides that do not match the ID of any check,
+// such as checks that have been misspelled, or that have been removed from kube-score
+func unknownSeverityChecks(severity map[string]config.Severity) []string {
+	known := make(map[string]struct{})
+	for _, c := range score.RegisterAllChecks(parser.Empty(), config.Configuration{}).All() {
+		known[c.ID] = struct{}{}
+	}
+
+	var unknown []string
+	for checkID := range severity {
+		if _, ok := known[checkID]; !ok {
+			unknown = append(unknown, checkID)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+func createBaseline(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ExitOnError)
+	printHelp := fs.Bool("help", false, "Print help")
+	outputFile := fs.String("output", "", "Write the baseline to this file instead of to STDOUT")
+	input := registerInputFlags(fs)
+	setDefault(fs, binName, "baseline", false)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse files: %w", err)
+	}
+
+	configuration, err := applyConfigFile(fs, *input.configFile)
+	if err != nil {
+		return err
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	cnf, err := input.configuration(fs, binName, "baseline", configuration)
+	if err != nil {
+		return err
+	}
+
+	scoreCard, err := scoreConfiguration(cnf)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *outputFile != "" {
+		fp, err := os.Create(*outputFile)
+		if err != nil {
+			return fmt.Errorf("failed to create baseline file: %w", err)
+		}
+		defer fp.Close()
+		w = fp
+	}
+
+	return baseline.New(scoreCard).Write(w)
+}
+
 func getOutputVersion(flagValue, format string) string {
 	if len(flagValue) > 0 {
 		return flagValue
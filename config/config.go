@@ -3,9 +3,12 @@ package config
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+
 	ks "github.com/younes-bami/kube-score/domain"
 )
 
@@ -19,6 +22,218 @@ type Configuration struct {
 	UseIgnoreChecksAnnotation             bool
 	UseOptionalChecksAnnotation           bool
 	KubernetesVersion                     Semver
+	SeverityOverrides                     map[string]Severity
+
+	// LiveObjects is set when the input has been exported from a running cluster
+	LiveObjects bool
+
+	// TolerateParseErrors makes documents that fail to parse be reported as findings, instead of aborting the run
+	TolerateParseErrors bool
+
+	// StrictDecoding removes fields with the wrong type from objects before they are decoded, so that they are
+	// reported by the strict-decoding check instead of failing to parse
+	StrictDecoding bool
+
+	// PodTemplates declares kinds that are not natively supported, but that have a pod template, such as
+	// custom workload resources. Objects of these kinds are scored with all pod checks.
+	PodTemplates []PodTemplate
+
+	// PodSecurityLevel is the Pod Security Standards profile that pods are evaluated against
+	PodSecurityLevel PodSecurityLevel
+
+	// HostPathAllowlist are the hostPath volumes that pods may use
+	HostPathAllowlist []HostPathRule
+
+	// AllowedCapabilities are the Linux capabilities that containers may add. DefaultAllowedCapabilities is used if not set.
+	AllowedCapabilities []string
+
+	// AllowedImageRegistries are the registries, or repository prefixes, that images may be pulled from.
+	// Images from all registries are allowed if not set.
+	AllowedImageRegistries []string
+
+	// RequireImageDigest requires that all images are pinned by a sha256 digest
+	RequireImageDigest bool
+
+	// ForbiddenImageTags are patterns of tags that images may not use, created with ParseImageTagPatterns
+	ForbiddenImageTags []*regexp.Regexp
+
+	// ResourceBounds are the allowed sizes of container resources. The first rule that matches a pod is used.
+	ResourceBounds []ResourceBoundsRule
+}
+
+// DefaultAllowedCapabilities are the capabilities that containers may add, if no other capabilities have been configured
+var DefaultAllowedCapabilities = []string{"NET_BIND_SERVICE"}
+
+// ParseImageTagPatterns compiles patterns of forbidden image tags. The patterns are regular expressions, and must
+// match the whole tag, so that "dev" forbids the tag "dev" but not "devel".
+func ParseImageTagPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid image tag pattern %q: %w", pattern, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// PodSecurityLevel is a profile of the Pod Security Standards
+type PodSecurityLevel string
+
+const (
+	PodSecurityLevelBaseline   PodSecurityLevel = "baseline"
+	PodSecurityLevelRestricted PodSecurityLevel = "restricted"
+)
+
+func (l PodSecurityLevel) Valid() bool {
+	switch l {
+	case PodSecurityLevelBaseline, PodSecurityLevelRestricted:
+		return true
+	}
+	return false
+}
+
+// PodTemplate declares where the pod template of a kind is located. Paths are separated by dots, such as "spec.template".
+type PodTemplate struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+
+	// Template is the path of the pod template
+	Template string `yaml:"template"`
+
+	// Replicas is the path of the number of replicas, and is optional
+	Replicas string `yaml:"replicas"`
+
+	// Selector is the path of the pod selector, and is optional. The selector can either be a label selector,
+	// or a map of labels.
+	Selector string `yaml:"selector"`
+}
+
+// Matches returns true if the pod template is declared for the apiVersion and kind
+func (p PodTemplate) Matches(apiVersion, kind string) bool {
+	return p.APIVersion == apiVersion && p.Kind == kind
+}
+
+// HostPathRule allows pods to use hostPath volumes of a path, or of any path below it
+type HostPathRule struct {
+	Path string `yaml:"path"`
+
+	// ReadOnly requires that the volume is mounted read-only in all containers
+	ReadOnly bool `yaml:"readOnly"`
+
+	// Namespaces limits the rule to objects in these namespaces. Objects without a namespace are in the "default" namespace.
+	Namespaces []string `yaml:"namespaces"`
+
+	// Annotation limits the rule to objects that have this annotation, such as node agents that opt in explicitly
+	Annotation string `yaml:"annotation"`
+}
+
+// Matches returns true if the rule applies to an object in the namespace and with the annotations.
+// If the rule is limited by both namespaces and an annotation, the object must match both.
+func (r HostPathRule) Matches(namespace string, annotations map[string]string) bool {
+	if !matchesNamespace(r.Namespaces, namespace) {
+		return false
+	}
+	if r.Annotation != "" {
+		if _, ok := annotations[r.Annotation]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesNamespace returns true if namespaces is empty, or if it contains the namespace.
+// Objects without a namespace are in the "default" namespace.
+func matchesNamespace(namespaces []string, namespace string) bool {
+	if len(namespaces) == 0 {
+		return true
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+	for _, ns := range namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceBoundsRule sets the allowed requests of containers, and how much higher than the requests that the limits
+// may be. All bounds are optional.
+type ResourceBoundsRule struct {
+	// Namespaces limits the rule to objects in these namespaces. Objects without a namespace are in the "default" namespace.
+	Namespaces []string `yaml:"namespaces"`
+
+	// Labels limits the rule to objects, or pod templates, that have all of these labels
+	Labels map[string]string `yaml:"labels"`
+
+	CPURequest    QuantityRange `yaml:"cpuRequest"`
+	MemoryRequest QuantityRange `yaml:"memoryRequest"`
+
+	// MaxLimitRequestRatio is the highest allowed limit divided by the request, for both CPU and memory
+	MaxLimitRequestRatio float64 `yaml:"maxLimitRequestRatio"`
+}
+
+// Matches returns true if the rule applies to an object in the namespace, and that has all labels of the rule
+func (r ResourceBoundsRule) Matches(namespace string, labels map[string]string) bool {
+	if !matchesNamespace(r.Namespaces, namespace) {
+		return false
+	}
+	for k, v := range r.Labels {
+		if value, ok := labels[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// QuantityRange is the allowed range of a resource quantity, such as "100m" or "1Gi". Both Min and Max are optional.
+type QuantityRange struct {
+	Min string `yaml:"min"`
+	Max string `yaml:"max"`
+}
+
+// Quantities returns the parsed bounds of the range. A bound that is not set is nil.
+func (r QuantityRange) Quantities() (min, max *resource.Quantity, err error) {
+	if r.Min != "" {
+		q, err := resource.ParseQuantity(r.Min)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid min %q: %w", r.Min, err)
+		}
+		min = &q
+	}
+	if r.Max != "" {
+		q, err := resource.ParseQuantity(r.Max)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid max %q: %w", r.Max, err)
+		}
+		max = &q
+	}
+	if min != nil && max != nil && min.Cmp(*max) > 0 {
+		return nil, nil, fmt.Errorf("min %s is higher than max %s", r.Min, r.Max)
+	}
+	return min, max, nil
+}
+
+// Severity is used to override the grade of a failing check
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityWarning  Severity = "warning"
+
+	// SeverityInfo reports the findings of the check, without failing the run
+	SeverityInfo Severity = "info"
+)
+
+func (s Severity) Valid() bool {
+	switch s {
+	case SeverityCritical, SeverityWarning, SeverityInfo:
+		return true
+	}
+	return false
 }
 
 type Semver struct {
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the project-level configuration file
+const FileName = ".kube-score.yaml"
+
+// File is the project-level configuration file.
+//
+// The keys in the file are named after the flags of "kube-score score", and explicitly set flags always
+// take precedence over the values in the file.
+type File struct {
+	ExitOneOnWarning                 *bool    `yaml:"exit-one-on-warning"`
+	IgnoreContainerCpuLimit          *bool    `yaml:"ignore-container-cpu-limit"`
+	IgnoreContainerMemoryLimit       *bool    `yaml:"ignore-container-memory-limit"`
+	Verbose                          *int     `yaml:"verbose"`
+	OutputFormat                     *string  `yaml:"output-format"`
+	OutputVersion                    *string  `yaml:"output-version"`
+	Color                            *string  `yaml:"color"`
+	EnableOptionalTest               []string `yaml:"enable-optional-test"`
+	IgnoreTest                       []string `yaml:"ignore-test"`
+	DisableIgnoreChecksAnnotations   *bool    `yaml:"disable-ignore-checks-annotations"`
+	DisableOptionalChecksAnnotations *bool    `yaml:"disable-optional-checks-annotations"`
+	KubernetesVersion                *string  `yaml:"kubernetes-version"`
+	Include                          []string `yaml:"include"`
+	Exclude                          []string `yaml:"exclude"`
+	TolerateParseErrors              *bool    `yaml:"tolerate-parse-errors"`
+	LiveObjects                      *bool    `yaml:"live-objects"`
+	PodSecurityLevel                 *string  `yaml:"pod-security-level"`
+	AllowCapability                  []string `yaml:"allow-capability"`
+	AllowImageRegistry               []string `yaml:"allow-image-registry"`
+	RequireImageDigest               *bool    `yaml:"require-image-digest"`
+	ForbidImageTag                   []string `yaml:"forbid-image-tag"`
+
+	// Severity remaps the grade of failing checks, keyed by check ID
+	Severity map[string]Severity `yaml:"severity"`
+
+	// PodTemplates declares the location of the pod template in kinds that are not natively supported
+	PodTemplates []PodTemplate `yaml:"pod-templates"`
+
+	// HostPathAllowlist declares the hostPath volumes that pods may use
+	HostPathAllowlist []HostPathRule `yaml:"host-path-allowlist"`
+
+	// ResourceBounds declares the allowed sizes of container resources
+	ResourceBounds []ResourceBoundsRule `yaml:"resource-bounds"`
+}
+
+// FindFile searches for a configuration file in dir, and then in each of its parent directories.
+// The path to the first found file is returned, or an empty string if no file was found.
+func FindFile(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		candidate := filepath.Join(dir, FileName)
+		if stat, err := os.Stat(candidate); err == nil && !stat.IsDir() {
+			return candidate, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// LoadFile reads and parses the configuration file at path
+func LoadFile(path string) (*File, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	f, err := ParseFile(fp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// ParseFile parses a configuration file. Unknown keys are treated as errors.
+func ParseFile(r io.Reader) (*File, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var f File
+
+	// An empty file is a valid configuration
+	if len(bytes.TrimSpace(data)) == 0 {
+		return &f, nil
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	for checkID, severity := range f.Severity {
+		if !severity.Valid() {
+			return nil, fmt.Errorf("invalid severity %q for %s, must be one of 'critical', 'warning' or 'info'", severity, checkID)
+		}
+	}
+
+	for _, podTemplate := range f.PodTemplates {
+		if podTemplate.APIVersion == "" || podTemplate.Kind == "" || podTemplate.Template == "" {
+			return nil, errors.New("pod-templates must set apiVersion, kind and template")
+		}
+	}
+
+	for _, rule := range f.HostPathAllowlist {
+		if !strings.HasPrefix(rule.Path, "/") {
+			return nil, fmt.Errorf("host-path-allowlist path %q must be absolute", rule.Path)
+		}
+	}
+
+	for _, rule := range f.ResourceBounds {
+		if _, _, err := rule.CPURequest.Quantities(); err != nil {
+			return nil, fmt.Errorf("resource-bounds cpuRequest: %w", err)
+		}
+		if _, _, err := rule.MemoryRequest.Quantities(); err != nil {
+			return nil, fmt.Errorf("resource-bounds memoryRequest: %w", err)
+		}
+		if rule.MaxLimitRequestRatio != 0 && rule.MaxLimitRequestRatio < 1 {
+			return nil, fmt.Errorf("resource-bounds maxLimitRequestRatio %v must be at least 1", rule.MaxLimitRequestRatio)
+		}
+	}
+
+	return &f, nil
+}
+
+// Flags returns the values of the file, keyed by the name of the flag that they correspond to.
+// Only values that are set in the file are returned.
+func (f *File) Flags() map[string][]string {
+	res := make(map[string][]string)
+
+	setBool := func(name string, v *bool) {
+		if v != nil {
+			res[name] = []string{strconv.FormatBool(*v)}
+		}
+	}
+	setString := func(name string, v *string) {
+		if v != nil {
+			res[name] = []string{*v}
+		}
+	}
+	setSlice := func(name string, v []string) {
+		if len(v) > 0 {
+			res[name] = v
+		}
+	}
+
+	setBool("exit-one-on-warning", f.ExitOneOnWarning)
+	setBool("ignore-container-cpu-limit", f.IgnoreContainerCpuLimit)
+	setBool("ignore-container-memory-limit", f.IgnoreContainerMemoryLimit)
+	if f.Verbose != nil {
+		res["verbose"] = []string{strconv.Itoa(*f.Verbose)}
+	}
+	setString("output-format", f.OutputFormat)
+	setString("output-version", f.OutputVersion)
+	setString("color", f.Color)
+	setSlice("enable-optional-test", f.EnableOptionalTest)
+	setSlice("ignore-test", f.IgnoreTest)
+	setBool("disable-ignore-checks-annotations", f.DisableIgnoreChecksAnnotations)
+	setBool("disable-optional-checks-annotations", f.DisableOptionalChecksAnnotations)
+	setString("kubernetes-version", f.KubernetesVersion)
+	setSlice("include", f.Include)
+	setSlice("exclude", f.Exclude)
+	setBool("tolerate-parse-errors", f.TolerateParseErrors)
+	setBool("live-objects", f.LiveObjects)
+	setString("pod-security-level", f.PodSecurityLevel)
+	setSlice("allow-capability", f.AllowCapability)
+	setSlice("allow-image-registry", f.AllowImageRegistry)
+	setBool("require-image-digest", f.RequireImageDigest)
+	setSlice("forbid-image-tag", f.ForbidImageTag)
+
+	return res
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFile(t *testing.T) {
+	f, err := ParseFile(strings.NewReader(`
+kubernetes-version: v1.25
+ignore-container-cpu-limit: true
+ignore-test:
+  - pod-networkpolicy
+  - container-image-tag
+verbose: 2
+pod-security-level: restricted
+live-objects: true
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"kubernetes-version":         {"v1.25"},
+		"ignore-container-cpu-limit": {"true"},
+		"ignore-test":                {"pod-networkpolicy", "container-image-tag"},
+		"verbose":                    {"2"},
+		"pod-security-level":         {"restricted"},
+		"live-objects":               {"true"},
+	}, f.Flags())
+}
+
+func TestParseFileEmpty(t *testing.T) {
+	f, err := ParseFile(strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.Empty(t, f.Flags())
+}
+
+func TestParseFileUnknownKey(t *testing.T) {
+	_, err := ParseFile(strings.NewReader("ignore-tests: [foo]\n"))
+	assert.Error(t, err)
+}
+
+func TestFindFile(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	assert.NoError(t, os.MkdirAll(nested, 0o755))
+
+	found, err := FindFile(nested)
+	assert.NoError(t, err)
+	assert.Equal(t, "", found)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(root, FileName), []byte("verbose: 1\n"), 0o644))
+
+	found, err = FindFile(nested)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, FileName), found)
+}
+
+func TestParseFileSeverity(t *testing.T) {
+	f, err := ParseFile(strings.NewReader(`
+severity:
+  service-type: critical
+  container-image-pull-policy: info
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Severity{
+		"service-type":                SeverityCritical,
+		"container-image-pull-policy": SeverityInfo,
+	}, f.Severity)
+
+	_, err = ParseFile(strings.NewReader("severity:\n  service-type: blocker\n"))
+	assert.Error(t, err)
+}
+
+func TestParseFilePodTemplates(t *testing.T) {
+	f, err := ParseFile(strings.NewReader(`
+pod-templates:
+  - apiVersion: argoproj.io/v1alpha1
+    kind: Rollout
+    template: spec.template
+    replicas: spec.replicas
+    selector: spec.selector
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []PodTemplate{{
+		APIVersion: "argoproj.io/v1alpha1",
+		Kind:       "Rollout",
+		Template:   "spec.template",
+		Replicas:   "spec.replicas",
+		Selector:   "spec.selector",
+	}}, f.PodTemplates)
+
+	_, err = ParseFile(strings.NewReader("pod-templates:\n  - kind: Rollout\n"))
+	assert.Error(t, err)
+}
+
+func TestParseFileHostPathAllowlist(t *testing.T) {
+	f, err := ParseFile(strings.NewReader(`
+host-path-allowlist:
+  - path: /var/log
+    readOnly: true
+    namespaces: [logging]
+    annotation: kube-score/node-agent
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []HostPathRule{{
+		Path:       "/var/log",
+		ReadOnly:   true,
+		Namespaces: []string{"logging"},
+		Annotation: "kube-score/node-agent",
+	}}, f.HostPathAllowlist)
+
+	_, err = ParseFile(strings.NewReader("host-path-allowlist:\n  - path: var/log\n"))
+	assert.Error(t, err)
+}
+
+func TestHostPathRuleMatches(t *testing.T) {
+	annotated := map[string]string{"kube-score/node-agent": "true"}
+
+	assert.True(t, HostPathRule{Path: "/var/log"}.Matches("apps", nil))
+	assert.True(t, HostPathRule{Path: "/var/log", Namespaces: []string{"default"}}.Matches("", nil))
+	assert.False(t, HostPathRule{Path: "/var/log", Namespaces: []string{"logging"}}.Matches("apps", annotated))
+	assert.True(t, HostPathRule{Path: "/var/log", Annotation: "kube-score/node-agent"}.Matches("apps", annotated))
+	assert.False(t, HostPathRule{Path: "/var/log", Annotation: "kube-score/node-agent"}.Matches("apps", nil))
+	assert.False(t, HostPathRule{Path: "/var/log", Namespaces: []string{"logging"}, Annotation: "kube-score/node-agent"}.Matches("apps", annotated))
+}
+
+func TestParseFileImagePolicy(t *testing.T) {
+	f, err := ParseFile(strings.NewReader(`
+allow-image-registry:
+  - registry.example.com
+require-image-digest: true
+forbid-image-tag:
+  - latest
+  - .*-SNAPSHOT
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"allow-image-registry": {"registry.example.com"},
+		"require-image-digest": {"true"},
+		"forbid-image-tag":     {"latest", ".*-SNAPSHOT"},
+	}, f.Flags())
+}
+
+func TestParseImageTagPatterns(t *testing.T) {
+	patterns, err := ParseImageTagPatterns([]string{"dev", ".*-SNAPSHOT"})
+	assert.NoError(t, err)
+	assert.True(t, patterns[0].MatchString("dev"))
+	assert.False(t, patterns[0].MatchString("devel"))
+	assert.True(t, patterns[1].MatchString("1.0-SNAPSHOT"))
+
+	_, err = ParseImageTagPatterns([]string{"v1.("})
+	assert.Error(t, err)
+}
+
+func TestParseFileResourceBounds(t *testing.T) {
+	f, err := ParseFile(strings.NewReader(`
+resource-bounds:
+  - namespaces: [batch]
+    labels:
+      tier: batch
+    cpuRequest:
+      max: "32"
+  - cpuRequest:
+      min: 50m
+      max: "8"
+    memoryRequest:
+      min: 128Mi
+    maxLimitRequestRatio: 4
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []ResourceBoundsRule{
+		{Namespaces: []string{"batch"}, Labels: map[string]string{"tier": "batch"}, CPURequest: QuantityRange{Max: "32"}},
+		{CPURequest: QuantityRange{Min: "50m", Max: "8"}, MemoryRequest: QuantityRange{Min: "128Mi"}, MaxLimitRequestRatio: 4},
+	}, f.ResourceBounds)
+
+	_, err = ParseFile(strings.NewReader("resource-bounds:\n  - memoryRequest:\n      min: 1GB\n"))
+	assert.Error(t, err)
+	_, err = ParseFile(strings.NewReader("resource-bounds:\n  - cpuRequest:\n      min: \"2\"\n      max: \"1\"\n"))
+	assert.Error(t, err)
+	_, err = ParseFile(strings.NewReader("resource-bounds:\n  - maxLimitRequestRatio: 0.5\n"))
+	assert.Error(t, err)
+}
+
+func TestResourceBoundsRuleMatches(t *testing.T) {
+	labels := map[string]string{"tier": "batch", "app": "foo"}
+
+	assert.True(t, ResourceBoundsRule{}.Matches("apps", nil))
+	assert.True(t, ResourceBoundsRule{Namespaces: []string{"default"}}.Matches("", nil))
+	assert.True(t, ResourceBoundsRule{Labels: map[string]string{"tier": "batch"}}.Matches("apps", labels))
+	assert.False(t, ResourceBoundsRule{Labels: map[string]string{"tier": "web"}}.Matches("apps", labels))
+	assert.False(t, ResourceBoundsRule{Namespaces: []string{"batch"}, Labels: map[string]string{"tier": "batch"}}.Matches("apps", labels))
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The types below are a minimal subset of the Gateway API (gateway.networking.k8s.io), with the fields that are
+// needed to check how routes, gateways and services refer to each other.
+
+// GatewayGroup is the API group of the Gateway API
+const GatewayGroup = "gateway.networking.k8s.io"
+
+// GatewaySpec is the spec of a Gateway
+type GatewaySpec struct {
+	GatewayClassName string     `json:"gatewayClassName"`
+	Listeners        []Listener `json:"listeners"`
+}
+
+// Listener is a port and protocol that a Gateway accepts traffic on
+type Listener struct {
+	Name          string         `json:"name"`
+	Hostname      *string        `json:"hostname,omitempty"`
+	Port          int32          `json:"port"`
+	Protocol      string         `json:"protocol"`
+	AllowedRoutes *AllowedRoutes `json:"allowedRoutes,omitempty"`
+}
+
+// AllowedRoutes limits the routes that can be attached to a listener
+type AllowedRoutes struct {
+	Namespaces *RouteNamespaces `json:"namespaces,omitempty"`
+	Kinds      []RouteGroupKind `json:"kinds,omitempty"`
+}
+
+// RouteNamespaces selects the namespaces that routes can be attached from. From is "Same" if not set, or "All" or "Selector".
+type RouteNamespaces struct {
+	From     *string               `json:"from,omitempty"`
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// RouteGroupKind is a kind of route, the group is GatewayGroup if not set
+type RouteGroupKind struct {
+	Group *string `json:"group,omitempty"`
+	Kind  string  `json:"kind"`
+}
+
+// RouteSpec is the spec of an HTTPRoute or a GRPCRoute
+type RouteSpec struct {
+	ParentRefs []ParentReference `json:"parentRefs,omitempty"`
+	Hostnames  []string          `json:"hostnames,omitempty"`
+	Rules      []RouteRule       `json:"rules,omitempty"`
+}
+
+// ParentReference refers to the Gateway that a route is attached to
+type ParentReference struct {
+	Group       *string `json:"group,omitempty"`
+	Kind        *string `json:"kind,omitempty"`
+	Namespace   *string `json:"namespace,omitempty"`
+	Name        string  `json:"name"`
+	SectionName *string `json:"sectionName,omitempty"`
+	Port        *int32  `json:"port,omitempty"`
+}
+
+// RouteRule is a rule of an HTTPRoute or a GRPCRoute
+type RouteRule struct {
+	Matches     []RouteMatch       `json:"matches,omitempty"`
+	BackendRefs []BackendReference `json:"backendRefs,omitempty"`
+}
+
+// RouteMatch is a match of an HTTPRoute rule, or of a GRPCRoute rule (that only has GRPCMethod and Headers)
+type RouteMatch struct {
+	Path        *PathMatch       `json:"path,omitempty"`
+	Method      *string          `json:"method,omitempty"`
+	GRPCMethod  *GRPCMethodMatch `json:"-"`
+	Headers     []HeaderMatch    `json:"headers,omitempty"`
+	QueryParams []HeaderMatch    `json:"queryParams,omitempty"`
+}
+
+// PathMatch matches the path of an HTTP request
+type PathMatch struct {
+	Type  *string `json:"type,omitempty"`
+	Value *string `json:"value,omitempty"`
+}
+
+// GRPCMethodMatch matches the service and method of a gRPC request
+type GRPCMethodMatch struct {
+	Type    *string `json:"type,omitempty"`
+	Service *string `json:"service,omitempty"`
+	Method  *string `json:"method,omitempty"`
+}
+
+// HeaderMatch matches a header or a query parameter of a request
+type HeaderMatch struct {
+	Type  *string `json:"type,omitempty"`
+	Name  string  `json:"name"`
+	Value string  `json:"value"`
+}
+
+// BackendReference refers to the backend that a rule forwards traffic to, usually a Service
+type BackendReference struct {
+	Group     *string `json:"group,omitempty"`
+	Kind      *string `json:"kind,omitempty"`
+	Name      string  `json:"name"`
+	Namespace *string `json:"namespace,omitempty"`
+	Port      *int32  `json:"port,omitempty"`
+}
+
+type Gateway interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	GatewaySpec() GatewaySpec
+	FileLocationer
+}
+
+type Gateways interface {
+	Gateways() []Gateway
+}
+
+// Route is an HTTPRoute or a GRPCRoute
+type Route interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	RouteSpec() RouteSpec
+	FileLocationer
+}
+
+type Routes interface {
+	Routes() []Route
+}
@@ -9,6 +9,7 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 type Check struct {
@@ -19,22 +20,58 @@ type Check struct {
 	Optional   bool
 }
 
+// NamedReader is a source of manifests. If the reader also implements FileLocationer, the location is used as
+// the location of the first line in the reader. This is used for content that has been rendered from another file.
 type NamedReader interface {
 	io.Reader
 	Name() string
 }
 
 type FileLocation struct {
-	Name string
-	Line int
+	Name   string
+	Line   int
+	Column int
+
+	// Fields resolves field paths in the object to their location in the file. It is nil if the location of the
+	// individual fields is unknown, as for rendered content.
+	Fields FieldLocator `json:"-"`
+}
+
+// FieldLocator finds the location of a field in an object, from a path such as "spec.template.spec.containers[0].image".
+type FieldLocator interface {
+	// FieldLocation returns the location of the field. If the field does not exist, the location of the nearest
+	// parent that exists is returned instead.
+	FieldLocation(path string) FileLocation
 }
 
 type BothMeta struct {
 	TypeMeta   metav1.TypeMeta
 	ObjectMeta metav1.ObjectMeta
 	FileLocationer
+
+	// SchemaErrors are the fields of the object that do not match the schema of its kind.
+	// Only kinds that kube-score knows the schema of are validated.
+	SchemaErrors []SchemaError
 }
 
+// SchemaError is a field of an object that does not match the schema of its kind
+type SchemaError struct {
+	Type SchemaErrorType
+
+	// FieldPath is the path of the field, such as "spec.template.spec.containers[0].resouces". It is empty if the
+	// path is unknown.
+	FieldPath string
+	Message   string
+}
+
+type SchemaErrorType int
+
+const (
+	SchemaErrorUnknownField SchemaErrorType = iota
+	SchemaErrorDuplicateField
+	SchemaErrorWrongType
+)
+
 type PodSpecer interface {
 	FileLocationer
 	GetTypeMeta() metav1.TypeMeta
@@ -42,6 +79,30 @@ type PodSpecer interface {
 	GetPodTemplateSpec() corev1.PodTemplateSpec
 }
 
+// PodTemplatePather is implemented by PodSpecers that have their pod template at another path than "spec.template".
+// The path is used to locate the findings of pod checks.
+type PodTemplatePather interface {
+	PodTemplatePath() string
+}
+
+// ReplicasGetter is implemented by PodSpecers that know their number of replicas. nil is returned if it is not set.
+type ReplicasGetter interface {
+	GetReplicas() *int32
+}
+
+// SelectorGetter is implemented by PodSpecers that know the selector of their pods. nil is returned if it is not set.
+type SelectorGetter interface {
+	GetSelector() *metav1.LabelSelector
+}
+
+// Workload is a PodSpecer that manages a number of replicas of its pods, such as ReplicaSets, ReplicationControllers
+// and custom workloads. Deployments and StatefulSets have checks of their own, and are not Workloads.
+type Workload interface {
+	PodSpecer
+	ReplicasGetter
+	SelectorGetter
+}
+
 type FileLocationer interface {
 	FileLocation() FileLocation
 }
@@ -113,6 +174,24 @@ type NetworkPolicies interface {
 	NetworkPolicies() []NetworkPolicy
 }
 
+type ServiceAccount interface {
+	ServiceAccount() corev1.ServiceAccount
+	FileLocationer
+}
+
+type ServiceAccounts interface {
+	ServiceAccounts() []ServiceAccount
+}
+
+type ConfigMap interface {
+	ConfigMap() corev1.ConfigMap
+	FileLocationer
+}
+
+type ConfigMaps interface {
+	ConfigMaps() []ConfigMap
+}
+
 type Ingresses interface {
 	Ingresses() []Ingress
 }
@@ -147,6 +226,35 @@ type HorizontalPodAutoscalers interface {
 	HorizontalPodAutoscalers() []HpaTargeter
 }
 
+// ParseError is a document that could not be parsed. The type and object metadata are set if they could be detected.
+type ParseError struct {
+	TypeMeta   metav1.TypeMeta
+	ObjectMeta metav1.ObjectMeta
+	Location   FileLocation
+	Err        error
+}
+
+func (p ParseError) FileLocation() FileLocation {
+	return p.Location
+}
+
+type ParseErrors interface {
+	ParseErrors() []ParseError
+}
+
+// UnknownObject is an object of a kind that kube-score has no specific support for, such as a custom resource.
+// Only the checks of the object metadata are run on it.
+type UnknownObject interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	Unstructured() unstructured.Unstructured
+	FileLocationer
+}
+
+type UnknownObjects interface {
+	UnknownObjects() []UnknownObject
+}
+
 type AllTypes interface {
 	Metas
 	Pods
@@ -159,4 +267,12 @@ type AllTypes interface {
 	CronJobs
 	PodDisruptionBudgets
 	HorizontalPodAutoscalers
+	ParseErrors
+	UnknownObjects
+	Gateways
+	Routes
+	ServiceAccounts
+	Roles
+	RoleBindings
+	ConfigMaps
 }
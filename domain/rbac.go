@@ -0,0 +1,31 @@
+package domain
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Role is a Role or a ClusterRole
+type Role interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	Rules() []rbacv1.PolicyRule
+	FileLocationer
+}
+
+type Roles interface {
+	Roles() []Role
+}
+
+// RoleBinding is a RoleBinding or a ClusterRoleBinding
+type RoleBinding interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	RoleRef() rbacv1.RoleRef
+	Subjects() []rbacv1.Subject
+	FileLocationer
+}
+
+type RoleBindings interface {
+	RoleBindings() []RoleBinding
+}
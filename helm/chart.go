@@ -0,0 +1,247 @@
+// Package helm renders Helm charts, so that the result can be scored without running "helm template".
+//
+// Charts are rendered with text/template and the subset of the Sprig functions that are commonly used by charts.
+// Values are read from values.yaml, from additional values files and from --set style arguments, in that order.
+// Unpacked subcharts in the charts/ directory are rendered together with the parent chart, unless they are disabled by
+// the condition or the tags of their dependency. Packaged subcharts (.tgz) are not supported.
+// The rendered objects keep track of the template file and the line that they were defined on.
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Options controls how a chart is rendered
+type Options struct {
+	// ReleaseName is used as .Release.Name
+	ReleaseName string
+
+	// Namespace is used as .Release.Namespace
+	Namespace string
+
+	// ValuesFiles are merged on top of the values.yaml of the chart, in order
+	ValuesFiles []string
+
+	// Set are values on the format "a.b=c", applied after the values files
+	Set []string
+
+	// KubeVersion is used as .Capabilities.KubeVersion, for example "v1.27.0"
+	KubeVersion string
+}
+
+// Metadata is the content of Chart.yaml, and is available to templates as .Chart
+type Metadata struct {
+	APIVersion   string       `yaml:"apiVersion"`
+	Name         string       `yaml:"name"`
+	Version      string       `yaml:"version"`
+	AppVersion   string       `yaml:"appVersion"`
+	Description  string       `yaml:"description"`
+	Type         string       `yaml:"type"`
+	KubeVersion  string       `yaml:"kubeVersion"`
+	Dependencies []Dependency `yaml:"dependencies"`
+}
+
+// Dependency is a subchart, as listed in the dependencies of Chart.yaml
+type Dependency struct {
+	Name       string   `yaml:"name"`
+	Version    string   `yaml:"version"`
+	Repository string   `yaml:"repository"`
+	Condition  string   `yaml:"condition"`
+	Tags       []string `yaml:"tags"`
+}
+
+type chart struct {
+	dir       string
+	metadata  Metadata
+	values    Values
+	subcharts []*chart
+
+	// packaged are the file names of the packaged subcharts in the charts/ directory
+	packaged []string
+}
+
+// loadChart reads Chart.yaml, values.yaml and the subcharts of the chart in dir
+func loadChart(dir string) (*chart, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "Chart.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chart: %w", err)
+	}
+
+	c := &chart{dir: dir}
+	if err := yaml.Unmarshal(data, &c.metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, "Chart.yaml"), err)
+	}
+	if c.metadata.Name == "" {
+		return nil, fmt.Errorf("%s has no name", filepath.Join(dir, "Chart.yaml"))
+	}
+
+	c.values = Values{}
+	valuesFile := filepath.Join(dir, "values.yaml")
+	if _, err := os.Stat(valuesFile); err == nil {
+		c.values, err = ReadValuesFile(valuesFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "charts"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			if strings.HasSuffix(entry.Name(), ".tgz") {
+				c.packaged = append(c.packaged, entry.Name())
+			}
+			continue
+		}
+		sub, err := loadChart(filepath.Join(dir, "charts", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		c.subcharts = append(c.subcharts, sub)
+	}
+	sort.Slice(c.subcharts, func(i, j int) bool {
+		return c.subcharts[i].metadata.Name < c.subcharts[j].metadata.Name
+	})
+
+	return c, nil
+}
+
+// resolveValues sets the values of all subcharts, and removes the subcharts that are disabled.
+// The values of a subchart are the defaults from its values.yaml, overridden by the values in the parent under the
+// name of the subchart. Global values are shared with all subcharts.
+// An error is returned if a packaged subchart is enabled, as it can not be rendered.
+func (c *chart) resolveValues(vals Values) error {
+	c.values = vals
+	global, _ := vals["global"].(map[string]interface{})
+
+	for _, fileName := range c.packaged {
+		dep, ok := c.packagedDependency(fileName)
+		if !ok || c.dependencyEnabled(dep, vals) {
+			return fmt.Errorf("packaged subchart %s is not supported, extract it in %s", fileName, filepath.Join(c.dir, "charts"))
+		}
+	}
+
+	var enabled []*chart
+	for _, sub := range c.subcharts {
+		if dep, ok := c.dependency(sub.metadata.Name); ok && !c.dependencyEnabled(dep, vals) {
+			continue
+		}
+		enabled = append(enabled, sub)
+	}
+	c.subcharts = enabled
+
+	for _, sub := range c.subcharts {
+		subVals := sub.values
+		if parentVals, ok := vals[sub.metadata.Name].(map[string]interface{}); ok {
+			subVals = mergeValues(subVals, parentVals)
+		}
+		if global != nil {
+			subGlobal, _ := subVals["global"].(map[string]interface{})
+			if subGlobal == nil {
+				subGlobal = map[string]interface{}{}
+			}
+			subVals["global"] = mergeValues(subGlobal, global)
+		}
+		if err := sub.resolveValues(subVals); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dependency returns the dependency with the name in Chart.yaml
+func (c *chart) dependency(name string) (Dependency, bool) {
+	for _, dep := range c.metadata.Dependencies {
+		if dep.Name == name {
+			return dep, true
+		}
+	}
+	return Dependency{}, false
+}
+
+// packagedDependency returns the dependency of a packaged subchart, that is named as "<name>-<version>.tgz"
+func (c *chart) packagedDependency(fileName string) (Dependency, bool) {
+	for _, dep := range c.metadata.Dependencies {
+		if fileName == dep.Name+"-"+dep.Version+".tgz" {
+			return dep, true
+		}
+	}
+	return Dependency{}, false
+}
+
+// dependencyEnabled returns true if the subchart is enabled by the values of the parent chart.
+// As in Helm, the first path in the condition that is set to a boolean takes precedence over the tags.
+// If no condition is set, the subchart is enabled if any of its tags is true, or if none of them are false.
+func (c *chart) dependencyEnabled(dep Dependency, vals Values) bool {
+	for _, path := range strings.Split(dep.Condition, ",") {
+		if enabled, ok := lookupBool(vals, strings.TrimSpace(path)); ok {
+			return enabled
+		}
+	}
+
+	tags, _ := vals["tags"].(map[string]interface{})
+	hasTrue, hasFalse := false, false
+	for _, tag := range dep.Tags {
+		if enabled, ok := tags[tag].(bool); ok {
+			hasTrue = hasTrue || enabled
+			hasFalse = hasFalse || !enabled
+		}
+	}
+	return hasTrue || !hasFalse
+}
+
+// lookupBool returns the boolean at a path such as "a.b.enabled"
+func lookupBool(vals Values, path string) (bool, bool) {
+	if path == "" {
+		return false, false
+	}
+	var cur interface{} = vals
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return false, false
+		}
+		cur = m[key]
+	}
+	b, ok := cur.(bool)
+	return b, ok
+}
+
+// templateFiles returns all files in the templates directory of the chart, relative to the chart directory
+func (c *chart) templateFiles() ([]string, error) {
+	var res []string
+	root := filepath.Join(c.dir, "templates")
+	err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(c.dir, p)
+		if err != nil {
+			return err
+		}
+		res = append(res, filepath.ToSlash(rel))
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return res, err
+}
+
+// isPartial returns true for files that only contain definitions, and that should not be rendered by themselves
+func isPartial(rel string) bool {
+	base := filepath.Base(rel)
+	return strings.HasPrefix(base, "_") || strings.EqualFold(base, "NOTES.txt")
+}
@@ -0,0 +1,556 @@
+package helm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// funcMap returns the Sprig style functions that are commonly used in Helm charts.
+// include and tpl are added by the renderer, as they need access to the parsed templates.
+func funcMap() template.FuncMap {
+	return template.FuncMap{
+		// Defaults and flow control
+		"default":  defaultValue,
+		"empty":    empty,
+		"coalesce": coalesce,
+		"ternary": func(vt, vf interface{}, cond bool) interface{} {
+			if cond {
+				return vt
+			}
+			return vf
+		},
+		"required": func(msg string, v interface{}) (interface{}, error) {
+			if empty(v) {
+				return nil, errors.New(msg)
+			}
+			return v, nil
+		},
+		"fail": func(msg string) (string, error) {
+			return "", errors.New(msg)
+		},
+
+		// Strings
+		"quote": func(v ...interface{}) string {
+			var res []string
+			for _, s := range v {
+				if s != nil {
+					res = append(res, strconv.Quote(toString(s)))
+				}
+			}
+			return strings.Join(res, " ")
+		},
+		"squote": func(v ...interface{}) string {
+			var res []string
+			for _, s := range v {
+				if s != nil {
+					res = append(res, "'"+toString(s)+"'")
+				}
+			}
+			return strings.Join(res, " ")
+		},
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      title,
+		"trim":       strings.TrimSpace,
+		"trimAll":    func(cutset, s string) string { return strings.Trim(s, cutset) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trunc":      trunc,
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"repeat":     func(count int, s string) string { return strings.Repeat(s, count) },
+		"indent":     indent,
+		"nindent":    func(spaces int, s string) string { return "\n" + indent(spaces, s) },
+		"split":      split,
+		"splitList":  func(sep, s string) []interface{} { return toInterfaceList(strings.Split(s, sep)) },
+		"join":       join,
+		"toString":   toString,
+		"toStrings":  toStrings,
+		"cat": func(v ...interface{}) string {
+			var res []string
+			for _, s := range v {
+				if s != nil {
+					res = append(res, toString(s))
+				}
+			}
+			return strings.Join(res, " ")
+		},
+		"kebabcase": func(s string) string { return caseWithSeparator(s, "-") },
+		"snakecase": func(s string) string { return caseWithSeparator(s, "_") },
+		"regexMatch": func(regex, s string) (bool, error) {
+			return regexp.MatchString(regex, s)
+		},
+		"regexReplaceAll": func(regex, s, repl string) (string, error) {
+			re, err := regexp.Compile(regex)
+			if err != nil {
+				return "", err
+			}
+			return re.ReplaceAllString(s, repl), nil
+		},
+
+		// Encoding
+		"b64enc":    func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+		"b64dec":    b64dec,
+		"sha256sum": func(s string) string { sum := sha256.Sum256([]byte(s)); return hex.EncodeToString(sum[:]) },
+		"toYaml":    toYAML,
+		"fromYaml":  fromYAML,
+		"toJson":    toJSON,
+		"fromJson":  fromJSON,
+
+		// Numbers
+		"int":     toInt64,
+		"int64":   toInt64,
+		"float64": toFloat64,
+		"add":     func(a, b interface{}) int64 { return toInt64(a) + toInt64(b) },
+		"add1":    func(a interface{}) int64 { return toInt64(a) + 1 },
+		"sub":     func(a, b interface{}) int64 { return toInt64(a) - toInt64(b) },
+		"mul":     func(a, b interface{}) int64 { return toInt64(a) * toInt64(b) },
+		"div":     div,
+		"mod":     mod,
+		"max": func(a interface{}, rest ...interface{}) int64 {
+			res := toInt64(a)
+			for _, r := range rest {
+				if v := toInt64(r); v > res {
+					res = v
+				}
+			}
+			return res
+		},
+		"min": func(a interface{}, rest ...interface{}) int64 {
+			res := toInt64(a)
+			for _, r := range rest {
+				if v := toInt64(r); v < res {
+					res = v
+				}
+			}
+			return res
+		},
+		"until": func(count int) []int {
+			res := make([]int, 0, count)
+			for i := 0; i < count; i++ {
+				res = append(res, i)
+			}
+			return res
+		},
+
+		// Lists
+		"list":    func(v ...interface{}) []interface{} { return v },
+		"append":  func(list interface{}, v interface{}) []interface{} { return append(toList(list), v) },
+		"prepend": func(list interface{}, v interface{}) []interface{} { return append([]interface{}{v}, toList(list)...) },
+		"concat": func(lists ...interface{}) []interface{} {
+			var res []interface{}
+			for _, l := range lists {
+				res = append(res, toList(l)...)
+			}
+			return res
+		},
+		"first": func(list interface{}) interface{} {
+			if l := toList(list); len(l) > 0 {
+				return l[0]
+			}
+			return nil
+		},
+		"last": func(list interface{}) interface{} {
+			if l := toList(list); len(l) > 0 {
+				return l[len(l)-1]
+			}
+			return nil
+		},
+		"has": func(needle interface{}, list interface{}) bool {
+			for _, v := range toList(list) {
+				if reflect.DeepEqual(v, needle) {
+					return true
+				}
+			}
+			return false
+		},
+		"uniq": func(list interface{}) []interface{} {
+			var res []interface{}
+			for _, v := range toList(list) {
+				found := false
+				for _, r := range res {
+					if reflect.DeepEqual(r, v) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					res = append(res, v)
+				}
+			}
+			return res
+		},
+		"compact": func(list interface{}) []interface{} {
+			var res []interface{}
+			for _, v := range toList(list) {
+				if !empty(v) {
+					res = append(res, v)
+				}
+			}
+			return res
+		},
+		"sortAlpha": func(list interface{}) []string {
+			res := toStrings(list)
+			sort.Strings(res)
+			return res
+		},
+
+		// Dictionaries
+		"dict": func(v ...interface{}) map[string]interface{} {
+			res := map[string]interface{}{}
+			for i := 0; i+1 < len(v); i += 2 {
+				res[toString(v[i])] = v[i+1]
+			}
+			return res
+		},
+		"set": func(d map[string]interface{}, key string, value interface{}) map[string]interface{} {
+			d[key] = value
+			return d
+		},
+		"unset": func(d map[string]interface{}, key string) map[string]interface{} {
+			delete(d, key)
+			return d
+		},
+		"hasKey": func(d map[string]interface{}, key string) bool {
+			_, ok := d[key]
+			return ok
+		},
+		"get": func(d map[string]interface{}, key string) interface{} {
+			if v, ok := d[key]; ok {
+				return v
+			}
+			return ""
+		},
+		"keys": func(dicts ...map[string]interface{}) []string {
+			var res []string
+			for _, d := range dicts {
+				for k := range d {
+					res = append(res, k)
+				}
+			}
+			sort.Strings(res)
+			return res
+		},
+		"pick": func(d map[string]interface{}, keys ...string) map[string]interface{} {
+			res := map[string]interface{}{}
+			for _, k := range keys {
+				if v, ok := d[k]; ok {
+					res[k] = v
+				}
+			}
+			return res
+		},
+		"omit": func(d map[string]interface{}, keys ...string) map[string]interface{} {
+			res := map[string]interface{}{}
+			for k, v := range d {
+				res[k] = v
+			}
+			for _, k := range keys {
+				delete(res, k)
+			}
+			return res
+		},
+		"merge":          merge(false),
+		"mergeOverwrite": merge(true),
+		"deepCopy":       func(v interface{}) interface{} { return fromYAMLValue(toYAML(v)) },
+
+		// Types
+		"kindIs": func(kind string, v interface{}) bool { return kindOf(v) == kind },
+		"kindOf": kindOf,
+		"typeOf": func(v interface{}) string { return fmt.Sprintf("%T", v) },
+		"typeIs": func(typ string, v interface{}) bool { return fmt.Sprintf("%T", v) == typ },
+
+		// Cluster lookups are not possible when rendering offline
+		"lookup": func(...interface{}) map[string]interface{} { return map[string]interface{}{} },
+	}
+}
+
+func empty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == 0
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+func defaultValue(d interface{}, given ...interface{}) interface{} {
+	if len(given) == 0 || empty(given[0]) {
+		return d
+	}
+	return given[0]
+}
+
+func coalesce(v ...interface{}) interface{} {
+	for _, val := range v {
+		if !empty(val) {
+			return val
+		}
+	}
+	return nil
+}
+
+func toString(v interface{}) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	case error:
+		return s.Error()
+	case fmt.Stringer:
+		return s.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func toList(v interface{}) []interface{} {
+	if v == nil {
+		return nil
+	}
+	if l, ok := v.([]interface{}); ok {
+		return l
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []interface{}{v}
+	}
+	res := make([]interface{}, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		res[i] = rv.Index(i).Interface()
+	}
+	return res
+}
+
+func toInterfaceList(s []string) []interface{} {
+	res := make([]interface{}, len(s))
+	for i, v := range s {
+		res[i] = v
+	}
+	return res
+}
+
+func toStrings(v interface{}) []string {
+	var res []string
+	for _, s := range toList(v) {
+		res = append(res, toString(s))
+	}
+	return res
+}
+
+func join(sep string, v interface{}) string {
+	return strings.Join(toStrings(v), sep)
+}
+
+func split(sep, s string) map[string]interface{} {
+	res := map[string]interface{}{}
+	for i, part := range strings.Split(s, sep) {
+		res["_"+strconv.Itoa(i)] = part
+	}
+	return res
+}
+
+func title(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+func trunc(length int, s string) string {
+	if length < 0 {
+		if len(s)+length > 0 {
+			return s[len(s)+length:]
+		}
+		return s
+	}
+	if len(s) > length {
+		return s[:length]
+	}
+	return s
+}
+
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+var caseBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+func caseWithSeparator(s, sep string) string {
+	s = caseBoundary.ReplaceAllString(s, "${1}"+sep+"${2}")
+	s = strings.NewReplacer("-", sep, "_", sep, " ", sep).Replace(s)
+	return strings.ToLower(s)
+}
+
+func b64dec(s string) string {
+	res, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return err.Error()
+	}
+	return string(res)
+}
+
+func toYAML(v interface{}) string {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(string(data), "\n")
+}
+
+func fromYAML(s string) map[string]interface{} {
+	res := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(s), &res); err != nil {
+		res["Error"] = err.Error()
+	}
+	return res
+}
+
+func fromYAMLValue(s string) interface{} {
+	var res interface{}
+	_ = yaml.Unmarshal([]byte(s), &res)
+	return res
+}
+
+func toJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func fromJSON(s string) map[string]interface{} {
+	res := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(s), &res); err != nil {
+		res["Error"] = err.Error()
+	}
+	return res
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case string:
+		i, _ := strconv.ParseInt(n, 10, 64)
+		return i
+	case nil:
+		return 0
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return int64(rv.Float())
+	case reflect.Bool:
+		if rv.Bool() {
+			return 1
+		}
+	}
+	return 0
+}
+
+func toFloat64(v interface{}) float64 {
+	switch n := v.(type) {
+	case string:
+		f, _ := strconv.ParseFloat(n, 64)
+		return f
+	case float64:
+		return n
+	case float32:
+		return float64(n)
+	}
+	return float64(toInt64(v))
+}
+
+func div(a, b interface{}) (int64, error) {
+	if toInt64(b) == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return toInt64(a) / toInt64(b), nil
+}
+
+func mod(a, b interface{}) (int64, error) {
+	if toInt64(b) == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return toInt64(a) % toInt64(b), nil
+}
+
+func kindOf(v interface{}) string {
+	if v == nil {
+		return "invalid"
+	}
+	return reflect.ValueOf(v).Kind().String()
+}
+
+func merge(overwrite bool) func(dst map[string]interface{}, srcs ...map[string]interface{}) map[string]interface{} {
+	var mergeInto func(dst, src map[string]interface{})
+	mergeInto = func(dst, src map[string]interface{}) {
+		for k, v := range src {
+			srcMap, srcIsMap := v.(map[string]interface{})
+			dstMap, dstIsMap := dst[k].(map[string]interface{})
+			switch {
+			case srcIsMap && dstIsMap:
+				mergeInto(dstMap, srcMap)
+			case overwrite:
+				dst[k] = v
+			default:
+				if _, ok := dst[k]; !ok || empty(dst[k]) {
+					dst[k] = v
+				}
+			}
+		}
+	}
+
+	return func(dst map[string]interface{}, srcs ...map[string]interface{}) map[string]interface{} {
+		for _, src := range srcs {
+			mergeInto(dst, src)
+		}
+		return dst
+	}
+}
+
+// executeToString executes a template by name and returns the output
+func executeToString(t *template.Template, name string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,164 @@
+package helm
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	ks "github.com/younes-bami/kube-score/domain"
+)
+
+func renderObjects(t *testing.T, dir string, opts Options) ([]map[string]interface{}, []ks.FileLocation) {
+	readers, err := Render(dir, opts)
+	assert.NoError(t, err)
+
+	var objs []map[string]interface{}
+	var locations []ks.FileLocation
+	for _, r := range readers {
+		data, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		var obj map[string]interface{}
+		assert.NoError(t, yaml.Unmarshal(data, &obj))
+		objs = append(objs, obj)
+		locations = append(locations, r.(ks.FileLocationer).FileLocation())
+	}
+	return objs, locations
+}
+
+func TestRenderDefaultValues(t *testing.T) {
+	objs, locations := renderObjects(t, "testdata/app", Options{})
+	assert.Len(t, objs, 3)
+
+	assert.Equal(t, []ks.FileLocation{
+		{Name: filepath.FromSlash("testdata/app/templates/deployment.yaml"), Line: 1},
+		{Name: filepath.FromSlash("testdata/app/templates/service.yaml"), Line: 3},
+		{Name: filepath.FromSlash("testdata/app/charts/redis/templates/service.yaml"), Line: 1},
+	}, locations)
+
+	deployment, service, redis := objs[0], objs[1], objs[2]
+
+	assert.Equal(t, "release-name-redis", get(redis, "metadata", "name"))
+	assert.Equal(t, "dev", get(redis, "metadata", "labels", "environment"))
+	assert.Equal(t, 6379, get(redis, "spec", "ports", 0, "port"))
+
+	assert.Equal(t, "release-name-app", get(deployment, "metadata", "name"))
+	assert.Equal(t, "1.2.3", get(deployment, "metadata", "labels", "app.kubernetes.io/version"))
+	assert.Equal(t, 1, get(deployment, "spec", "replicas"))
+	assert.Equal(t, "nginx:1.2.3", get(deployment, "spec", "template", "spec", "containers", 0, "image"))
+	assert.Nil(t, get(deployment, "spec", "template", "spec", "containers", 0, "resources"))
+
+	assert.Equal(t, "release-name-app", get(service, "metadata", "name"))
+	assert.Equal(t, 80, get(service, "spec", "ports", 0, "port"))
+}
+
+func TestRenderValuesFilesAndSet(t *testing.T) {
+	objs, locations := renderObjects(t, "testdata/app", Options{
+		ReleaseName: "prod",
+		ValuesFiles: []string{"testdata/prod-values.yaml"},
+		Set: []string{
+			"service.enabled=false,redis.enabled=false",
+			"resources.limits.memory=1Gi",
+			"global.environment=prod",
+		},
+	})
+	assert.Len(t, objs, 3)
+
+	assert.Equal(t, []ks.FileLocation{
+		{Name: filepath.FromSlash("testdata/app/templates/configmaps.yaml"), Line: 3},
+		{Name: filepath.FromSlash("testdata/app/templates/configmaps.yaml"), Line: 3},
+		{Name: filepath.FromSlash("testdata/app/templates/deployment.yaml"), Line: 1},
+	}, locations)
+
+	assert.Equal(t, "first", get(objs[0], "metadata", "name"))
+	assert.Equal(t, "0", get(objs[0], "data", "index"))
+	assert.Equal(t, "second", get(objs[1], "metadata", "name"))
+
+	deployment := objs[2]
+	assert.Equal(t, "prod-app", get(deployment, "metadata", "name"))
+	assert.Equal(t, 3, get(deployment, "spec", "replicas"))
+	assert.Equal(t, "nginx:2.0.0", get(deployment, "spec", "template", "spec", "containers", 0, "image"))
+	assert.Equal(t, "1Gi", get(deployment, "spec", "template", "spec", "containers", 0, "resources", "limits", "memory"))
+	assert.Equal(t, "prod", get(deployment, "spec", "template", "spec", "containers", 0, "env", 0, "value"))
+}
+
+func TestRenderSubchartTags(t *testing.T) {
+	objs, _ := renderObjects(t, "testdata/app", Options{
+		Set: []string{"tags.monitoring=true"},
+	})
+	assert.Len(t, objs, 4)
+	assert.Equal(t, "release-name-metrics", get(objs[2], "metadata", "name"))
+
+	// The condition takes precedence over the tags
+	objs, _ = renderObjects(t, "testdata/app", Options{
+		Set: []string{"tags.monitoring=true,metrics.enabled=false"},
+	})
+	assert.Len(t, objs, 3)
+}
+
+func TestRenderPackagedSubchart(t *testing.T) {
+	_, err := Render("testdata/packaged", Options{})
+	assert.ErrorContains(t, err, "packaged subchart database-1.0.0.tgz is not supported")
+
+	// Disabled packaged subcharts are not rendered
+	objs, _ := renderObjects(t, "testdata/packaged", Options{Set: []string{"database.enabled=false"}})
+	assert.Len(t, objs, 1)
+}
+
+func TestRenderMissingChart(t *testing.T) {
+	_, err := Render("testdata/does-not-exist", Options{})
+	assert.Error(t, err)
+}
+
+func TestParseSet(t *testing.T) {
+	vals := Values{"a": map[string]interface{}{"keep": "yes"}}
+	assert.NoError(t, parseSet(vals, `a.b=1,a.c=true,d=null,e=text,f\.g=h`))
+	assert.Equal(t, Values{
+		"a":   map[string]interface{}{"keep": "yes", "b": int64(1), "c": true},
+		"d":   nil,
+		"e":   "text",
+		"f.g": "h",
+	}, vals)
+
+	assert.Error(t, parseSet(vals, "novalue"))
+}
+
+func TestLocate(t *testing.T) {
+	source := []string{
+		"apiVersion: v1",
+		"kind: {{ .Values.kind }}",
+		"metadata:",
+		"  name: {{ .Release.Name }}",
+	}
+
+	// The first line is found verbatim
+	assert.Equal(t, 0, locate(source, "apiVersion: v1\nkind: Service", 0))
+
+	// Templated first lines are assumed to be right above the first line that is found
+	assert.Equal(t, 1, locate(source, "kind: Service\nmetadata:\n  name: foo", 0))
+
+	// The search restarts from the top if nothing is found after from
+	assert.Equal(t, 0, locate(source, "apiVersion: v1\nkind: Service", 2))
+}
+
+func get(obj interface{}, path ...interface{}) interface{} {
+	for _, p := range path {
+		switch key := p.(type) {
+		case string:
+			m, ok := obj.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			obj = m[key]
+		case int:
+			l, ok := obj.([]interface{})
+			if !ok || key >= len(l) {
+				return nil
+			}
+			obj = l[key]
+		}
+	}
+	return obj
+}
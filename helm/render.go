@@ -0,0 +1,343 @@
+package helm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	ks "github.com/younes-bami/kube-score/domain"
+)
+
+// Render renders the chart in dir, and returns one reader per rendered object.
+// The readers implement ks.FileLocationer, with the template file and the line that the object was defined on.
+func Render(dir string, opts Options) ([]ks.NamedReader, error) {
+	c, err := loadChart(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	vals := c.values
+	for _, fileName := range opts.ValuesFiles {
+		fileVals, err := ReadValuesFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+		vals = mergeValues(vals, fileVals)
+	}
+	for _, set := range opts.Set {
+		if err := parseSet(vals, set); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.resolveValues(vals); err != nil {
+		return nil, err
+	}
+
+	r := &renderer{opts: opts, sources: map[string]string{}}
+	if err := r.load(c, c.metadata.Name); err != nil {
+		return nil, err
+	}
+	if err := r.parse(); err != nil {
+		return nil, err
+	}
+
+	var res []ks.NamedReader
+	for _, f := range r.files {
+		if isPartial(f.name) {
+			continue
+		}
+		objects, err := r.render(f)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, objects...)
+	}
+	return res, nil
+}
+
+type renderedObject struct {
+	io.Reader
+	location ks.FileLocation
+}
+
+func (r renderedObject) Name() string {
+	return r.location.Name
+}
+
+func (r renderedObject) FileLocation() ks.FileLocation {
+	return r.location
+}
+
+// templateFile is a template of a chart or of one of its subcharts
+type templateFile struct {
+	// name is the name of the template as seen by Helm, such as "mychart/templates/deployment.yaml"
+	name     string
+	fileName string
+	chart    *chart
+}
+
+type renderer struct {
+	opts    Options
+	files   []templateFile
+	sources map[string]string
+	tmpl    *template.Template
+}
+
+// load reads the templates of the chart and its subcharts
+func (r *renderer) load(c *chart, prefix string) error {
+	rels, err := c.templateFiles()
+	if err != nil {
+		return err
+	}
+	for _, rel := range rels {
+		fileName := filepath.Join(c.dir, filepath.FromSlash(rel))
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			return err
+		}
+		name := path.Join(prefix, rel)
+		r.sources[name] = string(data)
+		r.files = append(r.files, templateFile{name: name, fileName: fileName, chart: c})
+	}
+
+	for _, sub := range c.subcharts {
+		if err := r.load(sub, path.Join(prefix, "charts", sub.metadata.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newTemplate creates a template with all functions, and with all chart templates parsed.
+// Definitions in any template (such as in _helpers.tpl) are available to all other templates.
+func (r *renderer) newTemplate() (*template.Template, error) {
+	t := template.New("gotpl").Option("missingkey=zero")
+	t.Funcs(funcMap())
+	t.Funcs(template.FuncMap{
+		"include": func(name string, data interface{}) (string, error) {
+			return executeToString(t, name, data)
+		},
+		"tpl": func(text string, data interface{}) (string, error) {
+			// A new template is created, as templates can not be modified after they have been executed
+			tt, err := r.newTemplate()
+			if err != nil {
+				return "", err
+			}
+			if _, err := tt.New("tpl").Parse(text); err != nil {
+				return "", err
+			}
+			res, err := executeToString(tt, "tpl", data)
+			return strings.ReplaceAll(res, "<no value>", ""), err
+		},
+	})
+
+	for _, f := range r.files {
+		if _, err := t.New(f.name).Parse(r.sources[f.name]); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", f.fileName, err)
+		}
+	}
+	return t, nil
+}
+
+func (r *renderer) parse() error {
+	t, err := r.newTemplate()
+	if err != nil {
+		return err
+	}
+	r.tmpl = t
+	return nil
+}
+
+// render executes a template, and splits the output into one reader per object
+func (r *renderer) render(f templateFile) ([]ks.NamedReader, error) {
+	out, err := executeToString(r.tmpl, f.name, r.data(f))
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", f.fileName, err)
+	}
+	out = strings.ReplaceAll(out, "<no value>", "")
+
+	sourceLines := strings.Split(r.sources[f.name], "\n")
+
+	var res []ks.NamedReader
+	next := 0
+	for _, doc := range splitDocuments(out) {
+		if isEmptyDocument(doc) {
+			continue
+		}
+		doc = trimLeadingBlankLines(doc)
+		line := locate(sourceLines, doc, next)
+		next = line + 1
+		res = append(res, renderedObject{
+			Reader:   bytes.NewReader([]byte(doc)),
+			location: ks.FileLocation{Name: f.fileName, Line: line + 1},
+		})
+	}
+	return res, nil
+}
+
+func (r *renderer) data(f templateFile) map[string]interface{} {
+	namespace := r.opts.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	releaseName := r.opts.ReleaseName
+	if releaseName == "" {
+		releaseName = "release-name"
+	}
+
+	return map[string]interface{}{
+		"Values": f.chart.values,
+		"Chart":  f.chart.metadata,
+		"Release": map[string]interface{}{
+			"Name":      releaseName,
+			"Namespace": namespace,
+			"Service":   "Helm",
+			"IsInstall": true,
+			"IsUpgrade": false,
+			"Revision":  1,
+		},
+		"Capabilities": map[string]interface{}{
+			"KubeVersion": newKubeVersion(r.opts.KubeVersion),
+			"APIVersions": apiVersions{},
+		},
+		"Template": map[string]interface{}{
+			"Name":     f.name,
+			"BasePath": path.Dir(f.name),
+		},
+		"Files": files{dir: f.chart.dir},
+	}
+}
+
+type kubeVersion struct {
+	Version    string
+	GitVersion string
+	Major      string
+	Minor      string
+}
+
+func (k kubeVersion) String() string {
+	return k.Version
+}
+
+func newKubeVersion(version string) kubeVersion {
+	if version == "" {
+		version = "v1.27.0"
+	}
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) == 2 {
+		version += ".0"
+		parts = append(parts, "0")
+	}
+	res := kubeVersion{Version: version, GitVersion: version}
+	if len(parts) >= 2 {
+		res.Major, res.Minor = parts[0], parts[1]
+	}
+	return res
+}
+
+// apiVersions is available as .Capabilities.APIVersions. As there is no cluster to ask, all versions are assumed to exist.
+type apiVersions struct{}
+
+func (apiVersions) Has(string) bool {
+	return true
+}
+
+// files is available as .Files, and gives access to the files in the chart
+type files struct {
+	dir string
+}
+
+func (f files) GetBytes(name string) []byte {
+	data, err := os.ReadFile(filepath.Join(f.dir, filepath.FromSlash(name)))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func (f files) Get(name string) string {
+	return string(f.GetBytes(name))
+}
+
+// splitDocuments splits rendered output into YAML documents
+func splitDocuments(s string) []string {
+	var res []string
+	var cur []string
+	for _, line := range strings.Split(s, "\n") {
+		if trimmed := strings.TrimRight(line, " \t"); trimmed == "---" || strings.HasPrefix(trimmed, "--- ") {
+			res = append(res, strings.Join(cur, "\n"))
+			cur = nil
+			continue
+		}
+		cur = append(cur, line)
+	}
+	return append(res, strings.Join(cur, "\n"))
+}
+
+func trimLeadingBlankLines(doc string) string {
+	lines := strings.Split(doc, "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isEmptyDocument returns true if the document only contains whitespace and comments
+func isEmptyDocument(doc string) bool {
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+// TemplateLine returns the line in the template source that a document rendered from it starts on, 1 indexed.
+// It is used to locate documents in the output of "helm template", that only contains the name of the template.
+func TemplateLine(source, doc string) int {
+	return locate(strings.Split(source, "\n"), doc, 0) + 1
+}
+
+// locate finds the line in the template source that is the nearest to the start of the rendered document.
+// The first line of the document that also exists verbatim in the source is used, starting the search at from.
+// If no line is found after from, as for documents rendered in a loop, the search is restarted from the top.
+// The returned line is zero indexed.
+func locate(source []string, doc string, from int) int {
+	var content []string
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			content = append(content, line)
+		}
+	}
+
+	for _, start := range []int{from, 0} {
+		for i, docLine := range content {
+			for j := start; j < len(source); j++ {
+				if strings.TrimSpace(source[j]) != docLine {
+					continue
+				}
+				// Lines before the match could not be found, as they are templated. Assume that they are right above.
+				// If that is before where the search started, the document is probably a repetition of an earlier one.
+				if j-i >= start {
+					return j - i
+				}
+				break
+			}
+		}
+	}
+
+	if from < len(source) {
+		return from
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+package helm
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Values = map[string]interface{}
+
+// ReadValuesFile reads a values file, such as values.yaml
+func ReadValuesFile(fileName string) (Values, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	vals := Values{}
+	if err := yaml.Unmarshal(data, &vals); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", fileName, err)
+	}
+	if vals == nil {
+		vals = Values{}
+	}
+	return vals, nil
+}
+
+// mergeValues merges src into dst recursively. Values in src take precedence.
+func mergeValues(dst, src Values) Values {
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			dst[k] = mergeValues(dstMap, srcMap)
+			continue
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+// parseSet applies a --set argument to the values. The argument is on the format "a.b=c",
+// and multiple values can be separated by commas, as in "a=1,b.c=2".
+func parseSet(vals Values, set string) error {
+	for _, assignment := range splitUnescaped(set, ',') {
+		idx := strings.Index(assignment, "=")
+		if idx < 0 {
+			return fmt.Errorf("invalid --set %q, expected key=value", assignment)
+		}
+
+		key, value := assignment[:idx], assignment[idx+1:]
+		path := splitUnescaped(key, '.')
+
+		cur := vals
+		for _, p := range path[:len(path)-1] {
+			next, ok := cur[p].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				cur[p] = next
+			}
+			cur = next
+		}
+		cur[path[len(path)-1]] = typedValue(value)
+	}
+	return nil
+}
+
+// splitUnescaped splits s on sep, unless sep is escaped with a backslash
+func splitUnescaped(s string, sep byte) []string {
+	var res []string
+	var cur strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && s[i+1] == sep {
+			cur.WriteByte(sep)
+			i++
+			continue
+		}
+		if s[i] == sep {
+			res = append(res, cur.String())
+			cur.Reset()
+			continue
+		}
+		cur.WriteByte(s[i])
+	}
+	return append(res, cur.String())
+}
+
+// typedValue converts a --set value to the same type as Helm would
+func typedValue(s string) interface{} {
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	return s
+}
@@ -0,0 +1,233 @@
+// Package kustomize renders kustomizations, so that the result can be scored without running "kustomize build".
+//
+// A subset of the features of kustomize is supported: resources and bases, patchesStrategicMerge,
+// patchesJson6902, patches, namePrefix, nameSuffix, namespace, commonLabels and commonAnnotations. Kustomizations
+// that use other fields are rejected.
+// The rendered objects keep track of the file and line that they were originally defined in.
+package kustomize
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	ks "github.com/younes-bami/kube-score/domain"
+)
+
+// Names of the kustomization file, in the order that they are looked for
+var kustomizationFileNames = []string{
+	"kustomization.yaml",
+	"kustomization.yml",
+	"Kustomization",
+}
+
+type kustomization struct {
+	APIVersion            string            `yaml:"apiVersion"`
+	Kind                  string            `yaml:"kind"`
+	Resources             []string          `yaml:"resources"`
+	Bases                 []string          `yaml:"bases"`
+	PatchesStrategicMerge []string          `yaml:"patchesStrategicMerge"`
+	PatchesJson6902       []patch           `yaml:"patchesJson6902"`
+	Patches               []patch           `yaml:"patches"`
+	NamePrefix            string            `yaml:"namePrefix"`
+	NameSuffix            string            `yaml:"nameSuffix"`
+	Namespace             string            `yaml:"namespace"`
+	CommonLabels          map[string]string `yaml:"commonLabels"`
+	CommonAnnotations     map[string]string `yaml:"commonAnnotations"`
+}
+
+type patch struct {
+	Path   string  `yaml:"path"`
+	Patch  string  `yaml:"patch"`
+	Target *target `yaml:"target"`
+}
+
+// resource is a single object in the output
+type resource struct {
+	obj      map[string]interface{}
+	location ks.FileLocation
+}
+
+// Build renders the kustomization in dir, and returns one reader per rendered object.
+// The readers implement ks.FileLocationer, with the location that the object was originally defined in.
+func Build(dir string) ([]ks.NamedReader, error) {
+	resources, err := build(dir, map[string]struct{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	var res []ks.NamedReader
+	for _, r := range resources {
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(r.obj); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", r.location.Name, err)
+		}
+		res = append(res, renderedObject{Reader: bytes.NewReader(buf.Bytes()), location: r.location})
+	}
+	return res, nil
+}
+
+type renderedObject struct {
+	io.Reader
+	location ks.FileLocation
+}
+
+func (r renderedObject) Name() string {
+	return r.location.Name
+}
+
+func (r renderedObject) FileLocation() ks.FileLocation {
+	return r.location
+}
+
+func build(dir string, visiting map[string]struct{}) ([]resource, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Protect against cyclic bases
+	if _, ok := visiting[dir]; ok {
+		return nil, fmt.Errorf("cycle detected in kustomization %s", dir)
+	}
+	visiting[dir] = struct{}{}
+	defer delete(visiting, dir)
+
+	k, err := loadKustomization(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []resource
+	for _, r := range append(k.Bases, k.Resources...) {
+		p := filepath.Join(dir, r)
+		stat, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load resource %s: %w", r, err)
+		}
+
+		var loaded []resource
+		if stat.IsDir() {
+			loaded, err = build(p, visiting)
+		} else {
+			loaded, err = loadResources(p)
+		}
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, loaded...)
+	}
+
+	if err := k.applyPatches(dir, resources); err != nil {
+		return nil, err
+	}
+
+	k.transform(resources)
+
+	return resources, nil
+}
+
+func loadKustomization(dir string) (*kustomization, error) {
+	for _, name := range kustomizationFileNames {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Unsupported fields, such as images or configMapGenerator, are rejected, as the
+		// rendered objects would silently differ from the output of "kustomize build"
+		var k kustomization
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&k); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to parse %s, only a subset of kustomize is supported: %w", filepath.Join(dir, name), err)
+		}
+		return &k, nil
+	}
+
+	return nil, fmt.Errorf("no kustomization file found in %s", dir)
+}
+
+// loadResources reads all objects in a (multi-document) YAML file
+func loadResources(fileName string) ([]resource, error) {
+	fp, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	var res []resource
+
+	dec := yaml.NewDecoder(fp)
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", fileName, err)
+		}
+
+		var obj map[string]interface{}
+		if err := doc.Decode(&obj); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", fileName, err)
+		}
+
+		// Empty document
+		if len(obj) == 0 {
+			continue
+		}
+
+		line := doc.Line
+		if len(doc.Content) > 0 {
+			line = doc.Content[0].Line
+		}
+
+		res = append(res, resource{
+			obj:      obj,
+			location: ks.FileLocation{Name: fileName, Line: line},
+		})
+	}
+
+	return res, nil
+}
+
+// readPatchDocuments reads all documents from an inline patch or a patch file
+func readPatchDocuments(dir string, p patch) ([]interface{}, error) {
+	data := []byte(p.Patch)
+	if p.Path != "" {
+		var err error
+		data, err = os.ReadFile(filepath.Join(dir, p.Path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read patch: %w", err)
+		}
+	}
+
+	var res []interface{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc interface{}
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse patch: %w", err)
+		}
+		if doc != nil {
+			res = append(res, doc)
+		}
+	}
+	return res, nil
+}
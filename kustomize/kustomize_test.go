@@ -0,0 +1,190 @@
+package kustomize
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	ks "github.com/younes-bami/kube-score/domain"
+)
+
+func buildObjects(t *testing.T, dir string) ([]map[string]interface{}, []ks.FileLocation) {
+	readers, err := Build(dir)
+	assert.NoError(t, err)
+
+	var objs []map[string]interface{}
+	var locations []ks.FileLocation
+	for _, r := range readers {
+		data, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		var obj map[string]interface{}
+		assert.NoError(t, yaml.Unmarshal(data, &obj))
+		objs = append(objs, obj)
+		locations = append(locations, r.(ks.FileLocationer).FileLocation())
+	}
+	return objs, locations
+}
+
+func TestBuildBase(t *testing.T) {
+	objs, locations := buildObjects(t, "testdata/base")
+	assert.Len(t, objs, 3)
+
+	base, _ := filepath.Abs("testdata/base")
+	assert.Equal(t, []ks.FileLocation{
+		{Name: filepath.Join(base, "deployment.yaml"), Line: 2},
+		{Name: filepath.Join(base, "service.yaml"), Line: 1},
+		{Name: filepath.Join(base, "service.yaml"), Line: 11},
+	}, locations)
+
+	deployment := objs[0]
+	assert.Equal(t, "web", field(deployment, "metadata", "labels", "app"))
+	assert.Equal(t, "web", field(deployment, "spec", "selector", "matchLabels", "app"))
+	assert.Equal(t, "web", field(deployment, "spec", "template", "metadata", "labels", "app"))
+	assert.Equal(t, "web", field(objs[1], "spec", "selector", "app"))
+}
+
+func TestBuildOverlay(t *testing.T) {
+	objs, _ := buildObjects(t, "testdata/overlay")
+	assert.Len(t, objs, 3)
+
+	deployment, service, ingress := objs[0], objs[1], objs[2]
+
+	assert.Equal(t, "prod-web", stringField(deployment, "metadata", "name"))
+	assert.Equal(t, "production", stringField(deployment, "metadata", "namespace"))
+	assert.Equal(t, "platform", field(deployment, "metadata", "annotations", "team"))
+	assert.Equal(t, "platform", field(deployment, "spec", "template", "metadata", "annotations", "team"))
+	assert.Equal(t, 5, field(deployment, "spec", "replicas"))
+
+	containers := field(deployment, "spec", "template", "spec", "containers").([]interface{})
+	assert.Len(t, containers, 1)
+	assert.Equal(t, "200m", field(containers[0], "resources", "limits", "cpu"))
+	assert.Equal(t, "100m", field(containers[0], "resources", "requests", "cpu"))
+
+	assert.Equal(t, "prod-web", stringField(service, "metadata", "name"))
+
+	// The reference to the renamed service is updated
+	paths := field(ingress, "spec", "rules").([]interface{})[0]
+	backend := field(paths, "http", "paths").([]interface{})[0]
+	assert.Equal(t, "prod-web", field(backend, "backend", "service", "name"))
+}
+
+func TestStrategicMerge(t *testing.T) {
+	original := map[string]interface{}{
+		"a": "1",
+		"b": map[string]interface{}{"c": "2", "d": "3"},
+		"env": []interface{}{
+			map[string]interface{}{"name": "x", "value": "1"},
+			map[string]interface{}{"name": "y", "value": "2"},
+		},
+		"scalars": []interface{}{"a", "b"},
+	}
+	patch := map[string]interface{}{
+		"a": nil,
+		"b": map[string]interface{}{"d": "4"},
+		"env": []interface{}{
+			map[string]interface{}{"name": "y", "$patch": "delete"},
+			map[string]interface{}{"name": "z", "value": "3"},
+		},
+		"scalars": []interface{}{"c"},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"b": map[string]interface{}{"c": "2", "d": "4"},
+		"env": []interface{}{
+			map[string]interface{}{"name": "x", "value": "1"},
+			map[string]interface{}{"name": "z", "value": "3"},
+		},
+		"scalars": []interface{}{"c"},
+	}, strategicMerge(original, patch))
+}
+
+func TestStrategicMergeKeys(t *testing.T) {
+	original := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"ports": []interface{}{
+				map[string]interface{}{"name": "http", "port": 80},
+			},
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name":         "app",
+					"ports":        []interface{}{map[string]interface{}{"name": "http", "containerPort": 8080}},
+					"volumeMounts": []interface{}{map[string]interface{}{"name": "data", "mountPath": "/data"}},
+				},
+			},
+		},
+	}
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			// Service ports are merged on port
+			"ports": []interface{}{
+				map[string]interface{}{"name": "web", "port": 80},
+			},
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name": "app",
+					// Container ports are merged on containerPort, and volume mounts on mountPath
+					"ports":        []interface{}{map[string]interface{}{"name": "http", "containerPort": 9090}},
+					"volumeMounts": []interface{}{map[string]interface{}{"name": "data", "mountPath": "/cache"}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"ports": []interface{}{
+				map[string]interface{}{"name": "web", "port": 80},
+			},
+			"containers": []interface{}{
+				map[string]interface{}{
+					"name": "app",
+					"ports": []interface{}{
+						map[string]interface{}{"name": "http", "containerPort": 8080},
+						map[string]interface{}{"name": "http", "containerPort": 9090},
+					},
+					"volumeMounts": []interface{}{
+						map[string]interface{}{"name": "data", "mountPath": "/data"},
+						map[string]interface{}{"name": "data", "mountPath": "/cache"},
+					},
+				},
+			},
+		},
+	}, strategicMerge(original, patch))
+}
+
+func TestJSON6902(t *testing.T) {
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"list": []interface{}{"a", "b"},
+			"a~b":  "escaped",
+		},
+	}
+	err := applyJSON6902(obj, []interface{}{
+		map[string]interface{}{"op": "add", "path": "/spec/list/-", "value": "c"},
+		map[string]interface{}{"op": "add", "path": "/spec/list/0", "value": "first"},
+		map[string]interface{}{"op": "remove", "path": "/spec/a~0b"},
+		map[string]interface{}{"op": "copy", "from": "/spec/list", "path": "/spec/copy"},
+		map[string]interface{}{"op": "test", "path": "/spec/list/1", "value": "a"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"list": []interface{}{"first", "a", "b", "c"},
+			"copy": []interface{}{"first", "a", "b", "c"},
+		},
+	}, obj)
+
+	err = applyJSON6902(obj, []interface{}{
+		map[string]interface{}{"op": "replace", "path": "/spec/missing", "value": "x"},
+	})
+	assert.Error(t, err)
+}
+
+func TestBuildUnsupportedField(t *testing.T) {
+	_, err := Build("testdata/unsupported")
+	assert.ErrorContains(t, err, "only a subset of kustomize is supported")
+	assert.ErrorContains(t, err, "field images not found")
+}
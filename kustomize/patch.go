@@ -0,0 +1,486 @@
+package kustomize
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type target struct {
+	Group     string `yaml:"group"`
+	Version   string `yaml:"version"`
+	Kind      string `yaml:"kind"`
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+}
+
+// matches returns true if the object is selected by the target. Names are matched as anchored regular expressions.
+func (t target) matches(obj map[string]interface{}) bool {
+	group, version := groupVersion(obj)
+	match := func(pattern, value string) bool {
+		if pattern == "" {
+			return true
+		}
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return pattern == value
+		}
+		return re.MatchString(value)
+	}
+
+	return match(t.Group, group) &&
+		match(t.Version, version) &&
+		match(t.Kind, stringField(obj, "kind")) &&
+		match(t.Name, stringField(obj, "metadata", "name")) &&
+		match(t.Namespace, stringField(obj, "metadata", "namespace"))
+}
+
+func (k *kustomization) applyPatches(dir string, resources []resource) error {
+	for _, p := range k.PatchesStrategicMerge {
+		sp := patch{Path: p}
+		// Strategic merge patches can be defined inline
+		if strings.Contains(p, "\n") {
+			sp = patch{Patch: p}
+		}
+		if err := applyPatch(dir, sp, resources, false); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range k.PatchesJson6902 {
+		if p.Target == nil {
+			return fmt.Errorf("patchesJson6902 requires a target")
+		}
+		if err := applyPatch(dir, p, resources, true); err != nil {
+			return err
+		}
+	}
+
+	// The type of patch in "patches" is detected from its contents
+	for _, p := range k.Patches {
+		if err := applyPatch(dir, p, resources, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func applyPatch(dir string, p patch, resources []resource, json6902 bool) error {
+	docs, err := readPatchDocuments(dir, p)
+	if err != nil {
+		return err
+	}
+
+	// A JSON6902 patch is a single list of operations
+	if len(docs) == 1 {
+		if ops, ok := docs[0].([]interface{}); ok {
+			if p.Target == nil {
+				return fmt.Errorf("JSON6902 patches require a target")
+			}
+			for i := range resources {
+				if !p.Target.matches(resources[i].obj) {
+					continue
+				}
+				if err := applyJSON6902(resources[i].obj, deepCopy(ops).([]interface{})); err != nil {
+					return fmt.Errorf("failed to apply patch to %s: %w", stringField(resources[i].obj, "metadata", "name"), err)
+				}
+			}
+			return nil
+		}
+	}
+	if json6902 {
+		return fmt.Errorf("patchesJson6902 must be a list of operations")
+	}
+
+	for _, doc := range docs {
+		patchObj, ok := doc.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("a strategic merge patch must be an object")
+		}
+
+		// Patches without an explicit target, target the object with the same kind and name as the patch
+		t := target{
+			Kind:      stringField(patchObj, "kind"),
+			Name:      regexp.QuoteMeta(stringField(patchObj, "metadata", "name")),
+			Namespace: regexp.QuoteMeta(stringField(patchObj, "metadata", "namespace")),
+		}
+		if p.Target != nil {
+			t = *p.Target
+		}
+
+		matched := false
+		for i := range resources {
+			if !t.matches(resources[i].obj) {
+				continue
+			}
+			matched = true
+
+			// The identity of a targeted object is not changed by patches with an explicit target
+			patchObj := patchObj
+			if p.Target != nil {
+				patchObj = withoutIdentity(patchObj)
+			}
+			resources[i].obj = strategicMerge(resources[i].obj, deepCopy(patchObj)).(map[string]interface{})
+		}
+
+		if !matched && p.Target == nil {
+			return fmt.Errorf("failed to find target for patch %s/%s", t.Kind, stringField(patchObj, "metadata", "name"))
+		}
+	}
+
+	return nil
+}
+
+func withoutIdentity(patchObj map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(patchObj))
+	for k, v := range patchObj {
+		if k == "apiVersion" || k == "kind" {
+			continue
+		}
+		res[k] = v
+	}
+	if metadata, ok := res["metadata"].(map[string]interface{}); ok {
+		m := make(map[string]interface{}, len(metadata))
+		for k, v := range metadata {
+			if k != "name" && k != "namespace" {
+				m[k] = v
+			}
+		}
+		res["metadata"] = m
+	}
+	return res
+}
+
+// The keys that lists of objects are merged on, keyed by the name of the list field, as in the patchMergeKey of the
+// Kubernetes types. Other lists are replaced.
+var mergeKeys = map[string]string{
+	"containers":                "name",
+	"initContainers":            "name",
+	"ephemeralContainers":       "name",
+	"volumes":                   "name",
+	"env":                       "name",
+	"imagePullSecrets":          "name",
+	"resourceClaims":            "name",
+	"volumeMounts":              "mountPath",
+	"volumeDevices":             "devicePath",
+	"hostAliases":               "ip",
+	"topologySpreadConstraints": "topologyKey",
+	"ports":                     "port",
+}
+
+// Container ports are merged on containerPort, and not on port as the ports of Services
+var containerFields = map[string]struct{}{
+	"containers":          {},
+	"initContainers":      {},
+	"ephemeralContainers": {},
+}
+
+// strategicMerge merges the patch into the original object, using a subset of the Kubernetes strategic merge patch semantics.
+// Maps are merged recursively, and null values remove the key. Lists of objects are merged by their merge key
+// (such as the name of a container), and all other lists are replaced. path is the names of the fields that lead to the
+// values, and is used to find the merge key of lists.
+func strategicMerge(original, patch interface{}, path ...string) interface{} {
+	switch p := patch.(type) {
+	case map[string]interface{}:
+		o, ok := original.(map[string]interface{})
+		if !ok {
+			o = map[string]interface{}{}
+		}
+		if directive, _ := p["$patch"].(string); directive == "replace" {
+			return withoutDirectives(p)
+		}
+
+		for k, v := range p {
+			if strings.HasPrefix(k, "$") {
+				continue
+			}
+			if v == nil {
+				delete(o, k)
+				continue
+			}
+			if vm, ok := v.(map[string]interface{}); ok {
+				if directive, _ := vm["$patch"].(string); directive == "delete" {
+					delete(o, k)
+					continue
+				}
+			}
+			o[k] = strategicMerge(o[k], v, append(path[:len(path):len(path)], k)...)
+		}
+		return o
+
+	case []interface{}:
+		o, ok := original.([]interface{})
+		if !ok {
+			return p
+		}
+		key := listMergeKey(path, o, p)
+		if key == "" {
+			return p
+		}
+
+		for _, item := range p {
+			itemMap := item.(map[string]interface{})
+			directive, _ := itemMap["$patch"].(string)
+
+			idx := -1
+			for i, existing := range o {
+				if existing.(map[string]interface{})[key] == itemMap[key] {
+					idx = i
+					break
+				}
+			}
+
+			switch {
+			case directive == "delete" && idx >= 0:
+				o = append(o[:idx], o[idx+1:]...)
+			case directive == "delete":
+			case idx >= 0:
+				o[idx] = strategicMerge(o[idx], itemMap, path...)
+			default:
+				o = append(o, withoutDirectives(itemMap))
+			}
+		}
+		return o
+
+	default:
+		return patch
+	}
+}
+
+// listMergeKey returns the key to merge the lists at path on, or an empty string if the lists should be replaced.
+// Lists are also replaced if any of their items does not have the merge key.
+func listMergeKey(path []string, lists ...[]interface{}) string {
+	if len(path) == 0 {
+		return ""
+	}
+	field := path[len(path)-1]
+	key, ok := mergeKeys[field]
+	if !ok {
+		return ""
+	}
+	if field == "ports" && len(path) >= 2 {
+		if _, ok := containerFields[path[len(path)-2]]; ok {
+			key = "containerPort"
+		}
+	}
+
+	for _, list := range lists {
+		for _, item := range list {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				return ""
+			}
+			if _, ok := m[key]; !ok {
+				return ""
+			}
+		}
+	}
+	return key
+}
+
+func withoutDirectives(m map[string]interface{}) map[string]interface{} {
+	res := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if !strings.HasPrefix(k, "$") {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// applyJSON6902 applies a list of RFC 6902 JSON patch operations to the object
+func applyJSON6902(obj map[string]interface{}, ops []interface{}) error {
+	for _, o := range ops {
+		op, ok := o.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("invalid patch operation")
+		}
+		opType, _ := op["op"].(string)
+		path, _ := op["path"].(string)
+		from, _ := op["from"].(string)
+
+		var err error
+		switch opType {
+		case "add":
+			err = pointerSet(obj, path, op["value"], true)
+		case "replace":
+			err = pointerSet(obj, path, op["value"], false)
+		case "remove":
+			_, err = pointerRemove(obj, path)
+		case "move":
+			var v interface{}
+			if v, err = pointerRemove(obj, from); err == nil {
+				err = pointerSet(obj, path, v, true)
+			}
+		case "copy":
+			var v interface{}
+			if v, err = pointerGet(obj, from); err == nil {
+				err = pointerSet(obj, path, deepCopy(v), true)
+			}
+		case "test":
+			var v interface{}
+			if v, err = pointerGet(obj, path); err == nil && fmt.Sprint(v) != fmt.Sprint(op["value"]) {
+				err = fmt.Errorf("test failed for %s", path)
+			}
+		default:
+			err = fmt.Errorf("unsupported operation %q", opType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	parts := strings.Split(pointer[1:], "/")
+	for i, p := range parts {
+		p = strings.ReplaceAll(p, "~1", "/")
+		parts[i] = strings.ReplaceAll(p, "~0", "~")
+	}
+	return parts, nil
+}
+
+// pointerParent resolves all but the last token of the pointer
+func pointerParent(obj map[string]interface{}, pointer string) (interface{}, string, error) {
+	parts, err := splitPointer(pointer)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(parts) == 0 {
+		return nil, "", fmt.Errorf("can not modify the root of the object")
+	}
+
+	var cur interface{} = obj
+	for _, p := range parts[:len(parts)-1] {
+		cur, err = child(cur, p)
+		if err != nil {
+			return nil, "", fmt.Errorf("%s: %w", pointer, err)
+		}
+	}
+	return cur, parts[len(parts)-1], nil
+}
+
+func child(cur interface{}, token string) (interface{}, error) {
+	switch c := cur.(type) {
+	case map[string]interface{}:
+		v, ok := c[token]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found", token)
+		}
+		return v, nil
+	case []interface{}:
+		idx, err := strconv.Atoi(token)
+		if err != nil || idx < 0 || idx >= len(c) {
+			return nil, fmt.Errorf("invalid index %q", token)
+		}
+		return c[idx], nil
+	default:
+		return nil, fmt.Errorf("can not traverse into %q", token)
+	}
+}
+
+func pointerGet(obj map[string]interface{}, pointer string) (interface{}, error) {
+	parent, last, err := pointerParent(obj, pointer)
+	if err != nil {
+		return nil, err
+	}
+	return child(parent, last)
+}
+
+func pointerSet(obj map[string]interface{}, pointer string, value interface{}, insert bool) error {
+	parent, last, err := pointerParent(obj, pointer)
+	if err != nil {
+		return err
+	}
+
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := p[last]; !ok && !insert {
+			return fmt.Errorf("%s: key %q not found", pointer, last)
+		}
+		p[last] = value
+		return nil
+	case []interface{}:
+		var idx int
+		if last == "-" && insert {
+			idx = len(p)
+		} else if idx, err = strconv.Atoi(last); err != nil || idx < 0 || idx > len(p) || (!insert && idx == len(p)) {
+			return fmt.Errorf("%s: invalid index %q", pointer, last)
+		}
+
+		if !insert {
+			p[idx] = value
+			return nil
+		}
+
+		// Lists are values, the grown list has to be written back to its parent
+		grown := append(p[:idx:idx], append([]interface{}{value}, p[idx:]...)...)
+		return replaceParent(obj, pointer, grown)
+	default:
+		return fmt.Errorf("%s: can not set value", pointer)
+	}
+}
+
+func pointerRemove(obj map[string]interface{}, pointer string) (interface{}, error) {
+	parent, last, err := pointerParent(obj, pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		v, ok := p[last]
+		if !ok {
+			return nil, fmt.Errorf("%s: key %q not found", pointer, last)
+		}
+		delete(p, last)
+		return v, nil
+	case []interface{}:
+		idx, err := strconv.Atoi(last)
+		if err != nil || idx < 0 || idx >= len(p) {
+			return nil, fmt.Errorf("%s: invalid index %q", pointer, last)
+		}
+		v := p[idx]
+		shrunk := append(p[:idx:idx], p[idx+1:]...)
+		return v, replaceParent(obj, pointer, shrunk)
+	default:
+		return nil, fmt.Errorf("%s: can not remove value", pointer)
+	}
+}
+
+// replaceParent replaces the list containing the last token of pointer
+func replaceParent(obj map[string]interface{}, pointer string, list []interface{}) error {
+	parentPointer := pointer[:strings.LastIndex(pointer, "/")]
+	if parentPointer == "" {
+		return fmt.Errorf("%s: the root of the object is not a list", pointer)
+	}
+	return pointerSet(obj, parentPointer, list, false)
+}
+
+func deepCopy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			res[k] = deepCopy(v)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(t))
+		for i, v := range t {
+			res[i] = deepCopy(v)
+		}
+		return res
+	default:
+		return v
+	}
+}
@@ -0,0 +1,202 @@
+package kustomize
+
+import (
+	"strings"
+)
+
+// Kinds that have a pod template, and the path to the template
+var podTemplatePaths = map[string][]string{
+	"Deployment":            {"spec", "template"},
+	"StatefulSet":           {"spec", "template"},
+	"DaemonSet":             {"spec", "template"},
+	"ReplicaSet":            {"spec", "template"},
+	"ReplicationController": {"spec", "template"},
+	"Job":                   {"spec", "template"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template"},
+}
+
+// transform applies the transformations of the kustomization to all resources
+func (k *kustomization) transform(resources []resource) {
+	renamed := make(map[string]string)
+
+	for _, r := range resources {
+		if k.NamePrefix != "" || k.NameSuffix != "" {
+			if name := stringField(r.obj, "metadata", "name"); name != "" {
+				newName := k.NamePrefix + name + k.NameSuffix
+				setField(r.obj, newName, "metadata", "name")
+				renamed[stringField(r.obj, "kind")+"/"+name] = newName
+			}
+		}
+
+		if k.Namespace != "" && !isClusterScoped(stringField(r.obj, "kind")) {
+			setField(r.obj, k.Namespace, "metadata", "namespace")
+		}
+
+		k.addLabels(r.obj)
+		k.addAnnotations(r.obj)
+	}
+
+	if len(renamed) > 0 {
+		for _, r := range resources {
+			updateNameReferences(r.obj, renamed)
+		}
+	}
+}
+
+func (k *kustomization) addLabels(obj map[string]interface{}) {
+	if len(k.CommonLabels) == 0 {
+		return
+	}
+
+	kind := stringField(obj, "kind")
+	mergeStringMap(obj, k.CommonLabels, "metadata", "labels")
+
+	if templatePath, ok := podTemplatePaths[kind]; ok {
+		mergeStringMap(obj, k.CommonLabels, append(append([]string{}, templatePath...), "metadata", "labels")...)
+
+		// The selector of a Job is generated by the API server
+		if kind != "Job" && kind != "CronJob" {
+			if kind == "ReplicationController" {
+				mergeStringMap(obj, k.CommonLabels, "spec", "selector")
+			} else {
+				mergeStringMap(obj, k.CommonLabels, "spec", "selector", "matchLabels")
+			}
+		}
+	}
+
+	switch kind {
+	case "Service":
+		mergeStringMap(obj, k.CommonLabels, "spec", "selector")
+	case "PodDisruptionBudget", "NetworkPolicy":
+		selectorField := "selector"
+		if kind == "NetworkPolicy" {
+			selectorField = "podSelector"
+		}
+		mergeStringMap(obj, k.CommonLabels, "spec", selectorField, "matchLabels")
+	}
+}
+
+func (k *kustomization) addAnnotations(obj map[string]interface{}) {
+	if len(k.CommonAnnotations) == 0 {
+		return
+	}
+
+	mergeStringMap(obj, k.CommonAnnotations, "metadata", "annotations")
+	if templatePath, ok := podTemplatePaths[stringField(obj, "kind")]; ok {
+		mergeStringMap(obj, k.CommonAnnotations, append(append([]string{}, templatePath...), "metadata", "annotations")...)
+	}
+}
+
+// updateNameReferences updates references between objects that have been renamed by namePrefix or nameSuffix
+func updateNameReferences(obj map[string]interface{}, renamed map[string]string) {
+	rename := func(kind string, path ...string) {
+		if name := stringField(obj, path...); name != "" {
+			if newName, ok := renamed[kind+"/"+name]; ok {
+				setField(obj, newName, path...)
+			}
+		}
+	}
+
+	switch stringField(obj, "kind") {
+	case "HorizontalPodAutoscaler":
+		rename(stringField(obj, "spec", "scaleTargetRef", "kind"), "spec", "scaleTargetRef", "name")
+	case "StatefulSet":
+		rename("Service", "spec", "serviceName")
+	case "Ingress":
+		renameIngressBackend(field(obj, "spec", "defaultBackend"), renamed)
+		renameIngressBackend(field(obj, "spec", "backend"), renamed)
+		rules, _ := field(obj, "spec", "rules").([]interface{})
+		for _, rule := range rules {
+			paths, _ := field(rule, "http", "paths").([]interface{})
+			for _, path := range paths {
+				renameIngressBackend(field(path, "backend"), renamed)
+			}
+		}
+	}
+}
+
+// renameIngressBackend renames the service of both networking.k8s.io/v1 and v1beta1 style backends
+func renameIngressBackend(backend interface{}, renamed map[string]string) {
+	b, ok := backend.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if name := stringField(b, "serviceName"); name != "" {
+		if newName, ok := renamed["Service/"+name]; ok {
+			b["serviceName"] = newName
+		}
+	}
+	if name := stringField(b, "service", "name"); name != "" {
+		if newName, ok := renamed["Service/"+name]; ok {
+			setField(b, newName, "service", "name")
+		}
+	}
+}
+
+var clusterScopedKinds = map[string]struct{}{
+	"Namespace":                      {},
+	"ClusterRole":                    {},
+	"ClusterRoleBinding":             {},
+	"CustomResourceDefinition":       {},
+	"PersistentVolume":               {},
+	"StorageClass":                   {},
+	"PriorityClass":                  {},
+	"IngressClass":                   {},
+	"ValidatingWebhookConfiguration": {},
+	"MutatingWebhookConfiguration":   {},
+}
+
+func isClusterScoped(kind string) bool {
+	_, ok := clusterScopedKinds[kind]
+	return ok
+}
+
+func groupVersion(obj map[string]interface{}) (group, version string) {
+	apiVersion := stringField(obj, "apiVersion")
+	if idx := strings.LastIndex(apiVersion, "/"); idx >= 0 {
+		return apiVersion[:idx], apiVersion[idx+1:]
+	}
+	return "", apiVersion
+}
+
+func field(obj interface{}, path ...string) interface{} {
+	cur := obj
+	for _, p := range path {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		cur = m[p]
+	}
+	return cur
+}
+
+func stringField(obj interface{}, path ...string) string {
+	s, _ := field(obj, path...).(string)
+	return s
+}
+
+// setField sets the value at path, and creates all missing maps along the way
+func setField(obj map[string]interface{}, value interface{}, path ...string) {
+	cur := obj
+	for _, p := range path[:len(path)-1] {
+		next, ok := cur[p].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			cur[p] = next
+		}
+		cur = next
+	}
+	cur[path[len(path)-1]] = value
+}
+
+func mergeStringMap(obj map[string]interface{}, values map[string]string, path ...string) {
+	m, ok := field(obj, path...).(map[string]interface{})
+	if !ok {
+		m = make(map[string]interface{})
+		setField(obj, m, path...)
+	}
+	for k, v := range values {
+		m[k] = v
+	}
+}
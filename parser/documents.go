@@ -0,0 +1,213 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// document is a single document in a YAML stream
+type document struct {
+	content []byte
+
+	// line is the line in the file that the first line of content is on
+	line int
+}
+
+// splitDocuments splits a YAML stream into its documents, and keeps track of the line that each document starts on.
+//
+// The stream is read with the YAML decoder, and the documents are cut at the positions of the document nodes.
+// Document markers and directives are not part of the documents, while comments before the content are kept.
+// Content on the same line as a "---" marker (such as "--- {}") is part of the document, and keeps its column.
+// Documents that only contain null, such as "---" followed by "---", are skipped.
+//
+// The decoder can not continue after a syntax error. The document with the error is then returned as is, and ends at
+// the next document marker, where decoding is restarted. As markers are never indented, they are found by looking
+// for lines that start with "---" or "...".
+func splitDocuments(data []byte, firstLine int) []document {
+	lines := bytes.Split(data, []byte("\n"))
+
+	var res []document
+	add := func(from, to int) {
+		if doc, ok := documentBetween(lines, from, to); ok {
+			doc.line += firstLine
+			res = append(res, doc)
+		}
+	}
+
+	for start := 0; start < len(lines); {
+		positions, errLine := decodePositions(lines, start)
+
+		end := len(lines)
+		restart := len(lines)
+		if errLine >= 0 {
+			lastContent := start - 1
+			if len(positions) > 0 {
+				lastContent = positions[len(positions)-1].content
+			}
+			if errLine <= lastContent {
+				errLine = lastContent + 1
+			}
+			end = errLine
+			if len(positions) == 0 {
+				end = start
+			}
+			if m := lastMarker(lines, lastContent+1, errLine); m >= 0 {
+				end = m
+			}
+			restart = nextMarker(lines, end+1, errLine)
+		}
+
+		for i, pos := range positions {
+			if pos.null {
+				continue
+			}
+			from := pos.marker
+			if pos.marker < 0 {
+				from = pos.start
+				if i == 0 {
+					// Comments before an implicit document are not part of the document node
+					from = start
+				}
+			}
+			to := end
+			if i+1 < len(positions) {
+				to = positions[i+1].start
+			}
+			add(from, to)
+		}
+
+		if errLine >= 0 {
+			add(end, restart)
+		}
+		start = restart
+	}
+
+	return res
+}
+
+// position is the position of a document in a stream, as zero indexed lines
+type position struct {
+	// start is the line that the document node starts on, that is the first directive, the "---" marker or the content
+	start int
+
+	// marker is the line of the "---" marker, or -1 for documents without a marker
+	marker int
+
+	// content is the line of the content of the document
+	content int
+
+	// null is true if the document only contains null
+	null bool
+}
+
+// decodePositions decodes the documents in lines, starting at the line start.
+// The line of the syntax error that stopped the decoder is returned, or -1 if all documents could be decoded.
+func decodePositions(lines [][]byte, start int) ([]position, int) {
+	var res []position
+	dec := yaml.NewDecoder(bytes.NewReader(bytes.Join(lines[start:], []byte("\n"))))
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return res, -1
+		}
+		if err != nil {
+			errLine := start
+			if len(res) > 0 {
+				errLine = res[len(res)-1].content + 1
+			}
+			if m := errorLinePattern.FindStringSubmatch(err.Error()); m != nil {
+				if l, convErr := strconv.Atoi(m[1]); convErr == nil && l > 0 {
+					errLine = start + l - 1
+				}
+			}
+			return res, errLine
+		}
+
+		pos := position{start: start + doc.Line - 1, marker: -1, content: start + doc.Line - 1, null: true}
+		if len(doc.Content) > 0 {
+			content := doc.Content[0]
+			pos.content = start + content.Line - 1
+			pos.null = content.Kind == yaml.ScalarNode && content.Tag == "!!null"
+		}
+		if m := lastMarker(lines, pos.start, pos.content); m >= 0 {
+			pos.marker = m
+		}
+		res = append(res, pos)
+	}
+}
+
+// documentBetween returns the document in the lines from and up to, but not including, to.
+// If the first line is a "---" marker, it is removed, or replaced with spaces if there is content on the same line.
+// False is returned if the document has no content.
+func documentBetween(lines [][]byte, from, to int) (document, bool) {
+	if from >= to || from >= len(lines) {
+		return document{}, false
+	}
+	if to > len(lines) {
+		to = len(lines)
+	}
+
+	docLines := append([][]byte{}, lines[from:to]...)
+	if first := docLines[0]; isDocumentMarker(first, "---") || isDocumentMarker(first, "...") {
+		rest := bytes.TrimSpace(first[3:])
+		if len(rest) == 0 || rest[0] == '#' {
+			docLines = docLines[1:]
+			from++
+		} else {
+			docLines[0] = append([]byte("   "), first[3:]...)
+		}
+	}
+
+	content := bytes.Join(docLines, []byte("\n"))
+	if len(bytes.TrimSpace(content)) == 0 {
+		return document{}, false
+	}
+	return document{content: content, line: from}, true
+}
+
+// lastMarker returns the last line from and up to, and including, to that is a document marker, or -1
+func lastMarker(lines [][]byte, from, to int) int {
+	for i := to; i >= from; i-- {
+		if i < len(lines) && (isDocumentMarker(lines[i], "---") || isDocumentMarker(lines[i], "...")) {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextMarker returns the line to restart decoding at after a syntax error, that is the first "---" marker, or the line
+// after the first "..." marker, from the line from and not before the line of the error
+func nextMarker(lines [][]byte, from, errLine int) int {
+	if errLine > from {
+		from = errLine
+	}
+	for i := from; i < len(lines); i++ {
+		if isDocumentMarker(lines[i], "---") {
+			return i
+		}
+		if isDocumentMarker(lines[i], "...") {
+			return i + 1
+		}
+	}
+	return len(lines)
+}
+
+// isDocumentMarker returns true if the line starts with the marker, followed by whitespace or the end of the line
+func isDocumentMarker(line []byte, marker string) bool {
+	if !bytes.HasPrefix(line, []byte(marker)) {
+		return false
+	}
+	if len(line) == len(marker) {
+		return true
+	}
+	switch line[len(marker)] {
+	case ' ', '\t', '\r':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitDocuments(t *testing.T) {
+	stream := "---\n" + // 1
+		"kind: A\n" + // 2
+		"--- # a comment\n" + // 3
+		"kind: B\n" + // 4
+		"data:\n" + // 5
+		"  file: |\n" + // 6
+		"    ---\n" + // 7
+		"    ...\n" + // 8
+		"--- \n" + // 9
+		"kind: C\n" + // 10
+		"...\n" + // 11
+		"%YAML 1.1\n" + // 12
+		"---\n" + // 13
+		"kind: D\n" + // 14
+		"--- {kind: E}\n" + // 15
+		"---\t\n" + // 16
+		"\n" + // 17
+		"kind: F\n" + // 18
+		"---\n" + // 19
+		"---\n" + // 20
+		"# Source: g.yaml\n" + // 21
+		"kind: G" // 22
+
+	docs := splitDocuments([]byte(stream), 1)
+
+	assert.Equal(t, []document{
+		{line: 2, content: []byte("kind: A")},
+		{line: 4, content: []byte("kind: B\ndata:\n  file: |\n    ---\n    ...")},
+		{line: 10, content: []byte("kind: C\n...")},
+		{line: 14, content: []byte("kind: D")},
+		{line: 15, content: []byte("    {kind: E}")},
+		{line: 17, content: []byte("\nkind: F")},
+		{line: 21, content: []byte("# Source: g.yaml\nkind: G")},
+	}, docs)
+}
+
+func TestSplitDocumentsOffset(t *testing.T) {
+	docs := splitDocuments([]byte("# comment\nkind: A\n---\nkind: B\n"), 10)
+	assert.Equal(t, []document{
+		{line: 10, content: []byte("# comment\nkind: A")},
+		{line: 13, content: []byte("kind: B\n")},
+	}, docs)
+}
+
+func TestSplitDocumentsSyntaxErrors(t *testing.T) {
+	stream := "kind: A\n" + // 1
+		"---\n" + // 2
+		"kind: B\n" + // 3
+		"  bad: x\n" + // 4
+		"data: |\n" + // 5
+		"  ---\n" + // 6
+		"---\n" + // 7
+		"kind: C\n" + // 8
+		"name: 'unterminated\n" + // 9
+		"---\n" + // 10
+		"kind: D\n" // 11
+
+	docs := splitDocuments([]byte(stream), 1)
+
+	assert.Equal(t, []document{
+		{line: 1, content: []byte("kind: A")},
+		{line: 3, content: []byte("kind: B\n  bad: x\ndata: |\n  ---")},
+		{line: 8, content: []byte("kind: C\nname: 'unterminated")},
+		{line: 11, content: []byte("kind: D\n")},
+	}, docs)
+}
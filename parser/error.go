@@ -1,7 +1,14 @@
 package parser
 
 import (
+	"regexp"
+	"strconv"
 	"strings"
+
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/younes-bami/kube-score/domain"
 )
 
 type parseErrors []error
@@ -23,3 +30,40 @@ func (p *parseErrors) AddIfErr(err error) {
 func (p parseErrors) Any() bool {
 	return len(p) > 0
 }
+
+// errorLinePattern matches the line number in errors from the YAML parsers, such as "yaml: line 3: could not find expected ':'"
+var errorLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// newParseError creates a ks.ParseError for a document that failed to parse.
+// The type and name of the object are detected if possible, and the location is set to the line of the error if known.
+func newParseError(fileName string, doc document, err error) ks.ParseError {
+	var meta struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+		Metadata   struct {
+			Name      string `yaml:"name"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"metadata"`
+	}
+	_ = yaml.Unmarshal(doc.content, &meta)
+
+	line := doc.line
+	if m := errorLinePattern.FindStringSubmatch(err.Error()); m != nil {
+		if errLine, convErr := strconv.Atoi(m[1]); convErr == nil && errLine > 0 {
+			line = doc.line + errLine - 1
+		}
+	}
+
+	return ks.ParseError{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: meta.APIVersion,
+			Kind:       meta.Kind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      meta.Metadata.Name,
+			Namespace: meta.Metadata.Namespace,
+		},
+		Location: ks.FileLocation{Name: fileName, Line: line},
+		Err:      err,
+	}
+}
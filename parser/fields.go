@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	ks "github.com/younes-bami/kube-score/domain"
+)
+
+// fieldIndex implements ks.FieldLocator, by looking up fields in the YAML node tree of an object
+type fieldIndex struct {
+	fileName string
+
+	// lineOffset is the line in the file that the first line of the document is on
+	lineOffset int
+
+	root *yaml.Node
+}
+
+func (f *fieldIndex) location(n *yaml.Node) ks.FileLocation {
+	return ks.FileLocation{
+		Name:   f.fileName,
+		Line:   f.lineOffset + n.Line - 1,
+		Column: n.Column,
+	}
+}
+
+// FieldLocation returns the location of the field at path. Paths are separated by dots, and list items are selected
+// with an index, as in "spec.containers[0].image". Keys that contain dots can be quoted, as in `metadata.labels["app.kubernetes.io/name"]`.
+// The location of a map field is the location of its key.
+func (f *fieldIndex) FieldLocation(path string) ks.FileLocation {
+	node := f.root
+	res := f.location(node)
+
+	for _, segment := range splitFieldPath(path) {
+		node = resolveAlias(node)
+
+		if idx, ok := segment.index(); ok {
+			if node.Kind != yaml.SequenceNode || idx >= len(node.Content) {
+				return res
+			}
+			node = node.Content[idx]
+			res = f.location(node)
+			continue
+		}
+
+		if node.Kind != yaml.MappingNode {
+			return res
+		}
+		found := false
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == segment.key {
+				res = f.location(node.Content[i])
+				node = node.Content[i+1]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return res
+		}
+	}
+
+	return res
+}
+
+func resolveAlias(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	return n
+}
+
+type fieldPathSegment struct {
+	key     string
+	isIndex bool
+}
+
+func (s fieldPathSegment) index() (int, bool) {
+	if !s.isIndex {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(s.key)
+	if err != nil || idx < 0 {
+		return 0, false
+	}
+	return idx, true
+}
+
+// splitFieldPath splits a path such as `spec.containers[0].env` or `metadata.labels["a.b/c"]` into its segments
+func splitFieldPath(path string) []fieldPathSegment {
+	var res []fieldPathSegment
+	var cur strings.Builder
+
+	flush := func() {
+		if cur.Len() > 0 {
+			res = append(res, fieldPathSegment{key: cur.String()})
+			cur.Reset()
+		}
+	}
+
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '.':
+			flush()
+		case '[':
+			flush()
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				cur.WriteString(path[i:])
+				i = len(path)
+				continue
+			}
+			inner := path[i+1 : i+end]
+			if unquoted, err := strconv.Unquote(inner); err == nil {
+				res = append(res, fieldPathSegment{key: unquoted})
+			} else {
+				res = append(res, fieldPathSegment{key: inner, isIndex: true})
+			}
+			i += end
+		default:
+			cur.WriteByte(path[i])
+		}
+	}
+	flush()
+
+	return res
+}
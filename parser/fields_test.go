@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ks "github.com/younes-bami/kube-score/domain"
+)
+
+func TestFieldLocation(t *testing.T) {
+	doc := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  labels:
+    app.kubernetes.io/name: foo
+spec:
+  template:
+    spec:
+      containers:
+        - name: first
+          image: first:1
+        - name: second
+          image: second:1
+          resources:
+            limits: &limits
+              cpu: 1
+      initContainers:
+        - name: init
+          resources:
+            limits: *limits
+`
+	index := &fieldIndex{fileName: "file.yaml", lineOffset: 10, root: objectNode([]byte(doc))}
+
+	cases := map[string]ks.FileLocation{
+		"":              {Name: "file.yaml", Line: 10, Column: 1},
+		"metadata.name": {Name: "file.yaml", Line: 13, Column: 3},
+		`metadata.labels["app.kubernetes.io/name"]`:         {Name: "file.yaml", Line: 15, Column: 5},
+		"spec.template.spec.containers[1]":                  {Name: "file.yaml", Line: 22, Column: 11},
+		"spec.template.spec.containers[1].image":            {Name: "file.yaml", Line: 23, Column: 11},
+		"spec.template.spec.containers[1].resources.limits": {Name: "file.yaml", Line: 25, Column: 13},
+
+		// Aliases are followed
+		"spec.template.spec.initContainers[0].resources.limits.cpu": {Name: "file.yaml", Line: 26, Column: 15},
+
+		// The nearest existing parent is used for fields that do not exist
+		"spec.template.spec.containers[0].resources.limits": {Name: "file.yaml", Line: 20, Column: 11},
+		"spec.template.spec.containers[5]":                  {Name: "file.yaml", Line: 19, Column: 7},
+		"metadata.name.foo":                                 {Name: "file.yaml", Line: 13, Column: 3},
+	}
+
+	for path, expected := range cases {
+		assert.Equal(t, expected, index.FieldLocation(path), path)
+	}
+}
+
+func TestSplitFieldPath(t *testing.T) {
+	assert.Equal(t, []fieldPathSegment{
+		{key: "spec"},
+		{key: "containers"},
+		{key: "0", isIndex: true},
+		{key: "env"},
+	}, splitFieldPath("spec.containers[0].env"))
+
+	assert.Equal(t, []fieldPathSegment{
+		{key: "metadata"},
+		{key: "annotations"},
+		{key: "kube-score/ignore"},
+	}, splitFieldPath(`metadata.annotations["kube-score/ignore"]`))
+}
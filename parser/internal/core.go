@@ -0,0 +1,73 @@
+package internal
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/younes-bami/kube-score/domain"
+)
+
+type Corev1ReplicationController struct {
+	corev1.ReplicationController
+	Location ks.FileLocation
+}
+
+func (d Corev1ReplicationController) FileLocation() ks.FileLocation {
+	return d.Location
+}
+
+func (d Corev1ReplicationController) GetTypeMeta() metav1.TypeMeta {
+	return d.TypeMeta
+}
+
+func (d Corev1ReplicationController) GetObjectMeta() metav1.ObjectMeta {
+	return d.ObjectMeta
+}
+
+func (d Corev1ReplicationController) GetPodTemplateSpec() corev1.PodTemplateSpec {
+	// The template is optional in ReplicationControllers
+	var template corev1.PodTemplateSpec
+	if d.Spec.Template != nil {
+		template = *d.Spec.Template
+	}
+	template.ObjectMeta.Namespace = d.ObjectMeta.Namespace
+	return template
+}
+
+func (d Corev1ReplicationController) GetReplicas() *int32 {
+	return d.Spec.Replicas
+}
+
+func (d Corev1ReplicationController) GetSelector() *metav1.LabelSelector {
+	if d.Spec.Selector == nil {
+		return nil
+	}
+	return &metav1.LabelSelector{MatchLabels: d.Spec.Selector}
+}
+
+type Corev1PodTemplate struct {
+	corev1.PodTemplate
+	Location ks.FileLocation
+}
+
+func (d Corev1PodTemplate) FileLocation() ks.FileLocation {
+	return d.Location
+}
+
+func (d Corev1PodTemplate) GetTypeMeta() metav1.TypeMeta {
+	return d.TypeMeta
+}
+
+func (d Corev1PodTemplate) GetObjectMeta() metav1.ObjectMeta {
+	return d.ObjectMeta
+}
+
+func (d Corev1PodTemplate) GetPodTemplateSpec() corev1.PodTemplateSpec {
+	d.Template.ObjectMeta.Namespace = d.ObjectMeta.Namespace
+	return d.Template
+}
+
+// PodTemplatePath returns the path of the pod template, which is at the top level of PodTemplates
+func (d Corev1PodTemplate) PodTemplatePath() string {
+	return "template"
+}
@@ -0,0 +1,125 @@
+package gateway
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	ks "github.com/younes-bami/kube-score/domain"
+)
+
+// The versions of the Gateway API that are supported
+var (
+	V1       = schema.GroupVersion{Group: ks.GatewayGroup, Version: "v1"}
+	V1beta1  = schema.GroupVersion{Group: ks.GatewayGroup, Version: "v1beta1"}
+	V1alpha2 = schema.GroupVersion{Group: ks.GatewayGroup, Version: "v1alpha2"}
+)
+
+type Gateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ks.GatewaySpec  `json:"spec"`
+	Location          ks.FileLocation `json:"-"`
+}
+
+// NewGateway converts a decoded Gateway
+func NewGateway(obj unstructured.Unstructured, location ks.FileLocation) (Gateway, error) {
+	var res Gateway
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &res)
+	res.Location = location
+	return res, err
+}
+
+func (g Gateway) FileLocation() ks.FileLocation {
+	return g.Location
+}
+
+func (g Gateway) GetTypeMeta() metav1.TypeMeta {
+	return g.TypeMeta
+}
+
+func (g Gateway) GetObjectMeta() metav1.ObjectMeta {
+	return g.ObjectMeta
+}
+
+func (g Gateway) GatewaySpec() ks.GatewaySpec {
+	return g.Spec
+}
+
+// Route is an HTTPRoute or a GRPCRoute
+type Route struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              ks.RouteSpec    `json:"spec"`
+	Location          ks.FileLocation `json:"-"`
+}
+
+// NewHTTPRoute converts a decoded HTTPRoute
+func NewHTTPRoute(obj unstructured.Unstructured, location ks.FileLocation) (Route, error) {
+	var res Route
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &res)
+	res.Location = location
+	return res, err
+}
+
+// grpcRoute has the fields of a GRPCRoute that differ from an HTTPRoute. The method of a match is an object, and not a string.
+type grpcRoute struct {
+	Spec struct {
+		Rules []struct {
+			Matches []struct {
+				Method *ks.GRPCMethodMatch `json:"method,omitempty"`
+			} `json:"matches,omitempty"`
+		} `json:"rules,omitempty"`
+	} `json:"spec"`
+}
+
+// NewGRPCRoute converts a decoded GRPCRoute
+func NewGRPCRoute(obj unstructured.Unstructured, location ks.FileLocation) (Route, error) {
+	var grpc grpcRoute
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &grpc); err != nil {
+		return Route{Location: location}, err
+	}
+
+	// Decode everything else as an HTTPRoute, without the methods
+	obj = *obj.DeepCopy()
+	if rules, ok, _ := unstructured.NestedSlice(obj.Object, "spec", "rules"); ok {
+		for _, rule := range rules {
+			ruleMap, _ := rule.(map[string]interface{})
+			matches, _ := ruleMap["matches"].([]interface{})
+			for _, match := range matches {
+				if matchMap, ok := match.(map[string]interface{}); ok {
+					delete(matchMap, "method")
+				}
+			}
+		}
+		_ = unstructured.SetNestedSlice(obj.Object, rules, "spec", "rules")
+	}
+
+	res, err := NewHTTPRoute(obj, location)
+	if err != nil {
+		return res, err
+	}
+	for i, rule := range grpc.Spec.Rules {
+		for j, match := range rule.Matches {
+			res.Spec.Rules[i].Matches[j].GRPCMethod = match.Method
+		}
+	}
+	return res, nil
+}
+
+func (r Route) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+func (r Route) GetTypeMeta() metav1.TypeMeta {
+	return r.TypeMeta
+}
+
+func (r Route) GetObjectMeta() metav1.ObjectMeta {
+	return r.ObjectMeta
+}
+
+func (r Route) RouteSpec() ks.RouteSpec {
+	return r.Spec
+}
@@ -0,0 +1,134 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+)
+
+// CustomPodSpecer is an object of a kind that is not natively supported, but that has been configured to have a pod
+// template, such as an Argo Rollout
+type CustomPodSpecer struct {
+	Obj        unstructured.Unstructured
+	ObjectMeta metav1.ObjectMeta
+	Template   corev1.PodTemplateSpec
+	Replicas   *int32
+	Selector   *metav1.LabelSelector
+	Path       string
+	Location   ks.FileLocation
+}
+
+// NewCustomPodSpecer extracts the pod template, and the optional replicas and selector, from obj.
+// False is returned if obj has no pod template at the configured path, such as an Argo Rollout that refers to
+// another workload with workloadRef.
+func NewCustomPodSpecer(obj unstructured.Unstructured, objectMeta metav1.ObjectMeta, podTemplate config.PodTemplate, location ks.FileLocation) (CustomPodSpecer, bool, error) {
+	res := CustomPodSpecer{
+		Obj:        obj,
+		ObjectMeta: objectMeta,
+		Path:       podTemplate.Template,
+		Location:   location,
+	}
+
+	template, found, err := unstructured.NestedMap(obj.Object, splitPath(podTemplate.Template)...)
+	if err != nil {
+		return res, true, fmt.Errorf("invalid pod template at %s: %w", podTemplate.Template, err)
+	}
+	if !found {
+		return res, false, nil
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(template, &res.Template); err != nil {
+		return res, true, fmt.Errorf("invalid pod template at %s: %w", podTemplate.Template, err)
+	}
+
+	if podTemplate.Replicas != "" {
+		replicas, found, err := unstructured.NestedInt64(obj.Object, splitPath(podTemplate.Replicas)...)
+		if err != nil {
+			return res, true, fmt.Errorf("invalid replicas at %s: %w", podTemplate.Replicas, err)
+		}
+		if found {
+			r := int32(replicas)
+			res.Replicas = &r
+		}
+	}
+
+	if podTemplate.Selector != "" {
+		selector, found, err := unstructured.NestedMap(obj.Object, splitPath(podTemplate.Selector)...)
+		if err != nil {
+			return res, true, fmt.Errorf("invalid selector at %s: %w", podTemplate.Selector, err)
+		}
+		if found {
+			res.Selector, err = labelSelector(selector)
+			if err != nil {
+				return res, true, fmt.Errorf("invalid selector at %s: %w", podTemplate.Selector, err)
+			}
+		}
+	}
+
+	return res, true, nil
+}
+
+func splitPath(path string) []string {
+	return strings.Split(path, ".")
+}
+
+// labelSelector converts a selector, that is either a label selector or a map of labels (as in Services)
+func labelSelector(selector map[string]interface{}) (*metav1.LabelSelector, error) {
+	var res metav1.LabelSelector
+	_, hasMatchLabels := selector["matchLabels"]
+	_, hasMatchExpressions := selector["matchExpressions"]
+	if hasMatchLabels || hasMatchExpressions {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selector, &res); err != nil {
+			return nil, err
+		}
+		return &res, nil
+	}
+
+	res.MatchLabels = make(map[string]string, len(selector))
+	for k, v := range selector {
+		value, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("the value of label %s is not a string", k)
+		}
+		res.MatchLabels[k] = value
+	}
+	return &res, nil
+}
+
+func (c CustomPodSpecer) FileLocation() ks.FileLocation {
+	return c.Location
+}
+
+func (c CustomPodSpecer) GetTypeMeta() metav1.TypeMeta {
+	return metav1.TypeMeta{
+		APIVersion: c.Obj.GetAPIVersion(),
+		Kind:       c.Obj.GetKind(),
+	}
+}
+
+func (c CustomPodSpecer) GetObjectMeta() metav1.ObjectMeta {
+	return c.ObjectMeta
+}
+
+func (c CustomPodSpecer) GetPodTemplateSpec() corev1.PodTemplateSpec {
+	c.Template.ObjectMeta.Namespace = c.ObjectMeta.Namespace
+	return c.Template
+}
+
+func (c CustomPodSpecer) PodTemplatePath() string {
+	return c.Path
+}
+
+func (c CustomPodSpecer) GetReplicas() *int32 {
+	return c.Replicas
+}
+
+func (c CustomPodSpecer) GetSelector() *metav1.LabelSelector {
+	return c.Selector
+}
@@ -0,0 +1,39 @@
+package internal
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/younes-bami/kube-score/domain"
+)
+
+type Appsv1ReplicaSet struct {
+	appsv1.ReplicaSet
+	Location ks.FileLocation
+}
+
+func (d Appsv1ReplicaSet) FileLocation() ks.FileLocation {
+	return d.Location
+}
+
+func (d Appsv1ReplicaSet) GetTypeMeta() metav1.TypeMeta {
+	return d.TypeMeta
+}
+
+func (d Appsv1ReplicaSet) GetObjectMeta() metav1.ObjectMeta {
+	return d.ObjectMeta
+}
+
+func (d Appsv1ReplicaSet) GetPodTemplateSpec() corev1.PodTemplateSpec {
+	d.Spec.Template.ObjectMeta.Namespace = d.ObjectMeta.Namespace
+	return d.Spec.Template
+}
+
+func (d Appsv1ReplicaSet) GetReplicas() *int32 {
+	return d.Spec.Replicas
+}
+
+func (d Appsv1ReplicaSet) GetSelector() *metav1.LabelSelector {
+	return d.Spec.Selector
+}
@@ -0,0 +1,20 @@
+package serviceaccount
+
+import (
+	v1 "k8s.io/api/core/v1"
+
+	ks "github.com/younes-bami/kube-score/domain"
+)
+
+type ServiceAccount struct {
+	Obj      v1.ServiceAccount
+	Location ks.FileLocation
+}
+
+func (s ServiceAccount) ServiceAccount() v1.ServiceAccount {
+	return s.Obj
+}
+
+func (s ServiceAccount) FileLocation() ks.FileLocation {
+	return s.Location
+}
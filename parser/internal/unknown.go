@@ -0,0 +1,34 @@
+package internal
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	ks "github.com/younes-bami/kube-score/domain"
+)
+
+// Unknown is an object of a kind that is not otherwise supported, such as a custom resource
+type Unknown struct {
+	Obj        unstructured.Unstructured
+	ObjectMeta metav1.ObjectMeta
+	Location   ks.FileLocation
+}
+
+func (u Unknown) FileLocation() ks.FileLocation {
+	return u.Location
+}
+
+func (u Unknown) GetTypeMeta() metav1.TypeMeta {
+	return metav1.TypeMeta{
+		APIVersion: u.Obj.GetAPIVersion(),
+		Kind:       u.Obj.GetKind(),
+	}
+}
+
+func (u Unknown) GetObjectMeta() metav1.ObjectMeta {
+	return u.ObjectMeta
+}
+
+func (u Unknown) Unstructured() unstructured.Unstructured {
+	return u.Obj
+}
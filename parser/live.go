@@ -0,0 +1,240 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Metadata fields that are populated by the API server, and that are never a part of the manifests
+var serverPopulatedMetadataFields = []string{
+	"managedFields",
+	"resourceVersion",
+	"uid",
+	"generation",
+	"creationTimestamp",
+	"deletionTimestamp",
+	"deletionGracePeriodSeconds",
+	"selfLink",
+}
+
+// Annotations that are added by kubectl or the controllers
+var serverPopulatedAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"deployment.kubernetes.io/revision",
+}
+
+// Fields of pod specs that are set to their default value by the API server, when they are not set in the manifest
+var serverDefaultedPodSpecFields = map[string]interface{}{
+	"dnsPolicy":     "ClusterFirst",
+	"schedulerName": "default-scheduler",
+	"restartPolicy": "Always",
+}
+
+// Fields of containers that are set to their default value by the API server, when they are not set in the manifest.
+// The default of imagePullPolicy depends on the image, see defaultImagePullPolicy.
+var serverDefaultedContainerFields = map[string]interface{}{
+	"terminationMessagePath":   "/dev/termination-log",
+	"terminationMessagePolicy": "File",
+}
+
+// Fields of workload specs that are set to their default value by the API server, when they are not set in the manifest
+var serverDefaultedWorkloadFields = map[string]map[string]interface{}{
+	"Deployment": {
+		"revisionHistoryLimit":    10,
+		"progressDeadlineSeconds": 600,
+	},
+	"StatefulSet": {
+		"revisionHistoryLimit": 10,
+	},
+	"DaemonSet": {
+		"revisionHistoryLimit": 10,
+	},
+}
+
+// sanitizeLiveObject removes the fields that are populated by the API server from an object that has been
+// exported from a running cluster, such as with "kubectl get all -o json".
+//
+// The returned bool is false if the object should not be scored at all, which is the case for objects that are
+// managed by another object (such as the Pods and ReplicaSets of a Deployment), as the findings would otherwise
+// be reported on both the owner and all of its children.
+//
+// Fields that the API server sets to their default value are removed as well, but only if they still have the
+// default value, as it is not possible to tell if the manifest set them explicitly.
+func sanitizeLiveObject(raw []byte) ([]byte, bool, error) {
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal(raw, &obj); err != nil {
+		return nil, false, err
+	}
+	if obj == nil {
+		return raw, true, nil
+	}
+
+	delete(obj, "status")
+
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	if metadata != nil {
+		if isControlledObject(metadata) || isDefaultKubernetesService(obj, metadata) {
+			return nil, false, nil
+		}
+		sanitizeMetadata(metadata)
+	}
+
+	if spec, ok := obj["spec"].(map[string]interface{}); ok {
+		kind, _ := obj["kind"].(string)
+		for field, value := range serverDefaultedWorkloadFields[kind] {
+			deleteIfEqual(spec, field, value)
+		}
+
+		// Remove the token volume that is injected by the ServiceAccount admission controller
+		if kind == "Pod" {
+			removeInjectedTokenVolume(spec)
+		}
+
+		if podSpec := liveObjectPodSpec(kind, spec); podSpec != nil {
+			removeDefaultedPodSpecFields(podSpec)
+		}
+	}
+
+	res, err := json.Marshal(obj)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode sanitized object: %w", err)
+	}
+	return res, true, nil
+}
+
+func sanitizeMetadata(metadata map[string]interface{}) {
+	for _, field := range serverPopulatedMetadataFields {
+		delete(metadata, field)
+	}
+
+	if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+		for _, annotation := range serverPopulatedAnnotations {
+			delete(annotations, annotation)
+		}
+		if len(annotations) == 0 {
+			delete(metadata, "annotations")
+		}
+	}
+}
+
+// isControlledObject returns true if the object has an owner that is the managing controller of the object
+func isControlledObject(metadata map[string]interface{}) bool {
+	owners, _ := metadata["ownerReferences"].([]interface{})
+	for _, o := range owners {
+		owner, _ := o.(map[string]interface{})
+		if controller, _ := owner["controller"].(bool); controller {
+			return true
+		}
+	}
+	return false
+}
+
+// isDefaultKubernetesService returns true for the "kubernetes" Service that exists in all clusters
+func isDefaultKubernetesService(obj, metadata map[string]interface{}) bool {
+	return obj["kind"] == "Service" &&
+		metadata["name"] == "kubernetes" &&
+		metadata["namespace"] == "default"
+}
+
+// liveObjectPodSpec returns the pod spec of the object, or nil if the object does not have one
+func liveObjectPodSpec(kind string, spec map[string]interface{}) map[string]interface{} {
+	var path []string
+	switch kind {
+	case "Pod":
+		return spec
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job", "PodTemplate":
+		path = []string{"template", "spec"}
+	case "CronJob":
+		path = []string{"jobTemplate", "spec", "template", "spec"}
+	default:
+		return nil
+	}
+
+	for _, field := range path {
+		spec, _ = spec[field].(map[string]interface{})
+		if spec == nil {
+			return nil
+		}
+	}
+	return spec
+}
+
+func removeDefaultedPodSpecFields(spec map[string]interface{}) {
+	for field, value := range serverDefaultedPodSpecFields {
+		deleteIfEqual(spec, field, value)
+	}
+	if securityContext, ok := spec["securityContext"].(map[string]interface{}); ok && len(securityContext) == 0 {
+		delete(spec, "securityContext")
+	}
+
+	for _, containerType := range []string{"initContainers", "containers"} {
+		containers, _ := spec[containerType].([]interface{})
+		for _, c := range containers {
+			container, _ := c.(map[string]interface{})
+			if container == nil {
+				continue
+			}
+			for field, value := range serverDefaultedContainerFields {
+				deleteIfEqual(container, field, value)
+			}
+			if image, ok := container["image"].(string); ok {
+				deleteIfEqual(container, "imagePullPolicy", defaultImagePullPolicy(image))
+			}
+		}
+	}
+}
+
+// defaultImagePullPolicy returns the imagePullPolicy that the API server sets on containers with the image.
+// Images with the latest tag, or without a tag and digest, are always pulled.
+func defaultImagePullPolicy(image string) string {
+	if strings.Contains(image, "@") {
+		return "IfNotPresent"
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") && image[i+1:] != "latest" {
+		return "IfNotPresent"
+	}
+	return "Always"
+}
+
+// deleteIfEqual deletes the field from obj if it is set to value.
+// Numbers are compared by their formatted value, as they can be decoded as both ints and floats.
+func deleteIfEqual(obj map[string]interface{}, field string, value interface{}) {
+	if v, ok := obj[field]; ok && fmt.Sprint(v) == fmt.Sprint(value) {
+		delete(obj, field)
+	}
+}
+
+func removeInjectedTokenVolume(spec map[string]interface{}) {
+	isInjected := func(name interface{}) bool {
+		s, _ := name.(string)
+		return strings.HasPrefix(s, "kube-api-access-")
+	}
+
+	if volumes, ok := spec["volumes"].([]interface{}); ok {
+		spec["volumes"] = filterByName(volumes, isInjected)
+	}
+
+	for _, containerType := range []string{"initContainers", "containers"} {
+		containers, _ := spec[containerType].([]interface{})
+		for _, c := range containers {
+			container, _ := c.(map[string]interface{})
+			if mounts, ok := container["volumeMounts"].([]interface{}); ok {
+				container["volumeMounts"] = filterByName(mounts, isInjected)
+			}
+		}
+	}
+}
+
+func filterByName(items []interface{}, remove func(name interface{}) bool) []interface{} {
+	res := make([]interface{}, 0, len(items))
+	for _, i := range items {
+		if item, ok := i.(map[string]interface{}); ok && remove(item["name"]) {
+			continue
+		}
+		res = append(res, i)
+	}
+	return res
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -12,6 +14,7 @@ import (
 	appsv1beta1 "k8s.io/api/apps/v1beta1"
 	appsv1beta2 "k8s.io/api/apps/v1beta2"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
 	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	batchv1 "k8s.io/api/batch/v1"
@@ -22,18 +25,26 @@ import (
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	policyv1 "k8s.io/api/policy/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 
 	"github.com/younes-bami/kube-score/config"
 	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/helm"
 	"github.com/younes-bami/kube-score/parser/internal"
+	internalconfigmap "github.com/younes-bami/kube-score/parser/internal/configmap"
 	internalcronjob "github.com/younes-bami/kube-score/parser/internal/cronjob"
+	internalgateway "github.com/younes-bami/kube-score/parser/internal/gateway"
 	internalnetpol "github.com/younes-bami/kube-score/parser/internal/networkpolicy"
 	internalpdb "github.com/younes-bami/kube-score/parser/internal/pdb"
 	internalpod "github.com/younes-bami/kube-score/parser/internal/pod"
+	internalrbac "github.com/younes-bami/kube-score/parser/internal/rbac"
 	internalservice "github.com/younes-bami/kube-score/parser/internal/service"
+	internalserviceaccount "github.com/younes-bami/kube-score/parser/internal/serviceaccount"
 )
 
 type Parser struct {
@@ -67,6 +78,11 @@ func (p *Parser) addToScheme() error {
 		batchv1beta1.AddToScheme,
 		policyv1beta1.AddToScheme,
 		policyv1.AddToScheme,
+		autoscalingv1.AddToScheme,
+		autoscalingv2beta1.AddToScheme,
+		autoscalingv2beta2.AddToScheme,
+		autoscalingv2.AddToScheme,
+		rbacv1.AddToScheme,
 	}
 
 	for _, adder := range adders {
@@ -95,6 +111,37 @@ type parsedObjects struct {
 	ingresses            []ks.Ingress // supports multiple versions of ingress
 	cronjobs             []ks.CronJob
 	hpaTargeters         []ks.HpaTargeter // all versions of HPAs
+	parseErrors          []ks.ParseError
+	unknownObjects       []ks.UnknownObject
+	gateways             []ks.Gateway
+	routes               []ks.Route // HTTPRoutes and GRPCRoutes
+	serviceAccounts      []ks.ServiceAccount
+	roles                []ks.Role        // Roles and ClusterRoles
+	roleBindings         []ks.RoleBinding // RoleBindings and ClusterRoleBindings
+	configMaps           []ks.ConfigMap
+}
+
+// add adds all objects in o to p
+func (p *parsedObjects) add(o *parsedObjects) {
+	p.bothMetas = append(p.bothMetas, o.bothMetas...)
+	p.pods = append(p.pods, o.pods...)
+	p.podspecers = append(p.podspecers, o.podspecers...)
+	p.networkPolicies = append(p.networkPolicies, o.networkPolicies...)
+	p.services = append(p.services, o.services...)
+	p.podDisruptionBudgets = append(p.podDisruptionBudgets, o.podDisruptionBudgets...)
+	p.deployments = append(p.deployments, o.deployments...)
+	p.statefulsets = append(p.statefulsets, o.statefulsets...)
+	p.ingresses = append(p.ingresses, o.ingresses...)
+	p.cronjobs = append(p.cronjobs, o.cronjobs...)
+	p.hpaTargeters = append(p.hpaTargeters, o.hpaTargeters...)
+	p.parseErrors = append(p.parseErrors, o.parseErrors...)
+	p.unknownObjects = append(p.unknownObjects, o.unknownObjects...)
+	p.gateways = append(p.gateways, o.gateways...)
+	p.routes = append(p.routes, o.routes...)
+	p.serviceAccounts = append(p.serviceAccounts, o.serviceAccounts...)
+	p.roles = append(p.roles, o.roles...)
+	p.roleBindings = append(p.roleBindings, o.roleBindings...)
+	p.configMaps = append(p.configMaps, o.configMaps...)
 }
 
 func (p *parsedObjects) Services() []ks.Service {
@@ -141,6 +188,38 @@ func (p *parsedObjects) HorizontalPodAutoscalers() []ks.HpaTargeter {
 	return p.hpaTargeters
 }
 
+func (p *parsedObjects) ParseErrors() []ks.ParseError {
+	return p.parseErrors
+}
+
+func (p *parsedObjects) UnknownObjects() []ks.UnknownObject {
+	return p.unknownObjects
+}
+
+func (p *parsedObjects) Gateways() []ks.Gateway {
+	return p.gateways
+}
+
+func (p *parsedObjects) Routes() []ks.Route {
+	return p.routes
+}
+
+func (p *parsedObjects) ServiceAccounts() []ks.ServiceAccount {
+	return p.serviceAccounts
+}
+
+func (p *parsedObjects) Roles() []ks.Role {
+	return p.roles
+}
+
+func (p *parsedObjects) RoleBindings() []ks.RoleBinding {
+	return p.roleBindings
+}
+
+func (p *parsedObjects) ConfigMaps() []ks.ConfigMap {
+	return p.configMaps
+}
+
 func Empty() ks.AllTypes {
 	return &parsedObjects{}
 }
@@ -157,30 +236,72 @@ func (p *Parser) ParseFiles(cnf config.Configuration) (ks.AllTypes, error) {
 		// Convert to unix style newlines
 		fullFile = bytes.ReplaceAll(fullFile, []byte("\r\n"), []byte("\n"))
 
+		fileName := namedReader.Name()
 		offset := 1 // Line numbers are 1 indexed
 
-		// Remove initial "---\n" if present
-		if bytes.HasPrefix(fullFile, []byte("---\n")) {
-			fullFile = fullFile[4:]
-			offset = 2
+		// Readers with rendered content know where the content was originally defined.
+		// The location of individual fields is not known, as the rendered content does not match the original file.
+		locationer, rendered := namedReader.(ks.FileLocationer)
+		if rendered {
+			fileName = locationer.FileLocation().Name
+			offset = locationer.FileLocation().Line
 		}
 
-		for _, fileContents := range bytes.Split(fullFile, []byte("\n---\n")) {
+		for _, doc := range splitDocuments(fullFile, offset) {
+			if len(bytes.TrimSpace(doc.content)) == 0 {
+				continue
+			}
 
-			if len(bytes.TrimSpace(fileContents)) > 0 {
-				if err := p.detectAndDecode(cnf, s, namedReader.Name(), offset, fileContents); err != nil {
-					return nil, err
-				}
+			var node *yaml.Node
+			if !rendered {
+				node = objectNode(doc.content)
 			}
 
-			offset += 2 + bytes.Count(fileContents, []byte("\n"))
+			// Decode into a separate set of objects, so that nothing from a document that fails to parse is scored
+			docObjects := &parsedObjects{}
+			err := p.detectAndDecode(cnf, docObjects, fileName, doc.line, doc.content, node)
+			switch {
+			case err == nil:
+				s.add(docObjects)
+			case cnf.TolerateParseErrors:
+				s.parseErrors = append(s.parseErrors, newParseError(fileName, doc, err))
+			default:
+				return nil, err
+			}
 		}
 	}
 
 	return s, nil
 }
 
-func (p *Parser) detectAndDecode(cnf config.Configuration, s *parsedObjects, fileName string, fileOffset int, raw []byte) error {
+// objectNode parses a document into a YAML node tree, that is used to find the location of fields in the object.
+// nil is returned if the document can not be parsed.
+func objectNode(raw []byte) *yaml.Node {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil || len(doc.Content) == 0 {
+		return nil
+	}
+	return doc.Content[0]
+}
+
+// listItemNode returns the node of an item in a List, or nil if it can not be found
+func listItemNode(list *yaml.Node, idx int) *yaml.Node {
+	if list == nil || list.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(list.Content); i += 2 {
+		if list.Content[i].Value == "items" {
+			items := list.Content[i+1]
+			if items.Kind == yaml.SequenceNode && idx < len(items.Content) {
+				return items.Content[idx]
+			}
+		}
+	}
+	return nil
+}
+
+// detectAndDecode decodes an object. node is the YAML node tree of the object, or nil if it is unknown.
+func (p *Parser) detectAndDecode(cnf config.Configuration, s *parsedObjects, fileName string, fileOffset int, raw []byte, node *yaml.Node) error {
 	var detect detectKind
 	err := yaml.Unmarshal(raw, &detect)
 	if err != nil {
@@ -196,8 +317,8 @@ func (p *Parser) detectAndDecode(cnf config.Configuration, s *parsedObjects, fil
 		if err != nil {
 			return err
 		}
-		for _, listItem := range list.Items {
-			err := p.detectAndDecode(cnf, s, fileName, fileOffset, listItem.Raw)
+		for i, listItem := range list.Items {
+			err := p.detectAndDecode(cnf, s, fileName, fileOffset, listItem.Raw, listItemNode(node, i))
 			if err != nil {
 				return err
 			}
@@ -205,11 +326,33 @@ func (p *Parser) detectAndDecode(cnf config.Configuration, s *parsedObjects, fil
 		return nil
 	}
 
-	err = p.decodeItem(cnf, s, detectedVersion, fileName, fileOffset, raw)
+	if cnf.LiveObjects {
+		sanitized, ok, err := sanitizeLiveObject(raw)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		raw = sanitized
+	}
+
+	fileLocation := detectFileLocation(fileName, fileOffset, raw, node)
+
+	schemaErrors, raw, err := p.validateSchema(cnf, detectedVersion, raw, node)
 	if err != nil {
 		return err
 	}
 
+	metasBefore := len(s.bothMetas)
+	err = p.decodeItem(cnf, s, detectedVersion, fileLocation, raw)
+	if err != nil {
+		return err
+	}
+	for i := metasBefore; i < len(s.bothMetas); i++ {
+		s.bothMetas[i].SchemaErrors = schemaErrors
+	}
+
 	return nil
 }
 
@@ -222,25 +365,51 @@ func (p *Parser) decode(data []byte, object runtime.Object) error {
 	return nil
 }
 
-func detectFileLocation(fileName string, fileOffset int, fileContents []byte) ks.FileLocation {
+func detectFileLocation(fileName string, fileOffset int, fileContents []byte, node *yaml.Node) ks.FileLocation {
 	// If the object YAML begins with a Helm style "# Source: " comment
 	// Use the information in there as the file name
 	firstRow := string(bytes.Split(fileContents, []byte("\n"))[0])
 	helmTemplatePrefix := "# Source: "
 	if strings.HasPrefix(firstRow, helmTemplatePrefix) {
+		templateName := firstRow[len(helmTemplatePrefix):]
 		return ks.FileLocation{
-			Name: firstRow[len(helmTemplatePrefix):],
-			Line: 1, // Set line to 1 as the line definition gets lost in Helm
+			Name: templateName,
+			Line: helmTemplateLine(templateName, fileContents),
 		}
 	}
 
-	return ks.FileLocation{
+	location := ks.FileLocation{
 		Name: fileName,
 		Line: fileOffset,
 	}
+	if node != nil {
+		location.Fields = &fieldIndex{fileName: fileName, lineOffset: fileOffset, root: node}
+	}
+	return location
 }
 
-func (p *Parser) decodeItem(cnf config.Configuration, s *parsedObjects, detectedVersion schema.GroupVersionKind, fileName string, fileOffset int, fileContents []byte) error {
+// helmTemplateLine returns the line in the Helm template that a document was rendered from.
+// Helm writes the path of the template relative to the parent directory of the chart. If the template can not be
+// read from there, the line is set to 1.
+func helmTemplateLine(templateName string, doc []byte) int {
+	source, err := os.ReadFile(filepath.FromSlash(templateName))
+	if err != nil {
+		return 1
+	}
+	return helm.TemplateLine(string(source), string(doc))
+}
+
+// findPodTemplate returns the configured pod template of a kind, if any
+func findPodTemplate(podTemplates []config.PodTemplate, apiVersion, kind string) (config.PodTemplate, bool) {
+	for _, podTemplate := range podTemplates {
+		if podTemplate.Matches(apiVersion, kind) {
+			return podTemplate, true
+		}
+	}
+	return config.PodTemplate{}, false
+}
+
+func (p *Parser) decodeItem(cnf config.Configuration, s *parsedObjects, detectedVersion schema.GroupVersionKind, fileLocation ks.FileLocation, fileContents []byte) error {
 	addPodSpeccer := func(ps ks.PodSpecer) {
 		s.podspecers = append(s.podspecers, ps)
 		s.bothMetas = append(s.bothMetas, ks.BothMeta{
@@ -250,8 +419,6 @@ func (p *Parser) decodeItem(cnf config.Configuration, s *parsedObjects, detected
 		})
 	}
 
-	fileLocation := detectFileLocation(fileName, fileOffset, fileContents)
-
 	var errs parseErrors
 
 	switch detectedVersion {
@@ -262,6 +429,16 @@ func (p *Parser) decodeItem(cnf config.Configuration, s *parsedObjects, detected
 		s.pods = append(s.pods, p)
 		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: pod.TypeMeta, ObjectMeta: pod.ObjectMeta, FileLocationer: p})
 
+	case corev1.SchemeGroupVersion.WithKind("ReplicationController"):
+		var rc corev1.ReplicationController
+		errs.AddIfErr(p.decode(fileContents, &rc))
+		addPodSpeccer(internal.Corev1ReplicationController{ReplicationController: rc, Location: fileLocation})
+
+	case corev1.SchemeGroupVersion.WithKind("PodTemplate"):
+		var podTemplate corev1.PodTemplate
+		errs.AddIfErr(p.decode(fileContents, &podTemplate))
+		addPodSpeccer(internal.Corev1PodTemplate{PodTemplate: podTemplate, Location: fileLocation})
+
 	case batchv1.SchemeGroupVersion.WithKind("Job"):
 		var job batchv1.Job
 		errs.AddIfErr(p.decode(fileContents, &job))
@@ -319,6 +496,11 @@ func (p *Parser) decodeItem(cnf config.Configuration, s *parsedObjects, detected
 		errs.AddIfErr(p.decode(fileContents, &statefulSet))
 		addPodSpeccer(internal.Appsv1beta2StatefulSet{StatefulSet: statefulSet, Location: fileLocation})
 
+	case appsv1.SchemeGroupVersion.WithKind("ReplicaSet"):
+		var replicaset appsv1.ReplicaSet
+		errs.AddIfErr(p.decode(fileContents, &replicaset))
+		addPodSpeccer(internal.Appsv1ReplicaSet{ReplicaSet: replicaset, Location: fileLocation})
+
 	case appsv1.SchemeGroupVersion.WithKind("DaemonSet"):
 		var daemonset appsv1.DaemonSet
 		errs.AddIfErr(p.decode(fileContents, &daemonset))
@@ -346,6 +528,45 @@ func (p *Parser) decodeItem(cnf config.Configuration, s *parsedObjects, detected
 		s.services = append(s.services, serv)
 		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: service.TypeMeta, ObjectMeta: service.ObjectMeta, FileLocationer: serv})
 
+	case corev1.SchemeGroupVersion.WithKind("ServiceAccount"):
+		var serviceAccount corev1.ServiceAccount
+		errs.AddIfErr(p.decode(fileContents, &serviceAccount))
+		sa := internalserviceaccount.ServiceAccount{Obj: serviceAccount, Location: fileLocation}
+		s.serviceAccounts = append(s.serviceAccounts, sa)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: serviceAccount.TypeMeta, ObjectMeta: serviceAccount.ObjectMeta, FileLocationer: sa})
+
+	case corev1.SchemeGroupVersion.WithKind("ConfigMap"):
+		var configMap corev1.ConfigMap
+		errs.AddIfErr(p.decode(fileContents, &configMap))
+		cm := internalconfigmap.ConfigMap{Obj: configMap, Location: fileLocation}
+		s.configMaps = append(s.configMaps, cm)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: configMap.TypeMeta, ObjectMeta: configMap.ObjectMeta, FileLocationer: cm})
+
+	case rbacv1.SchemeGroupVersion.WithKind("Role"):
+		var role rbacv1.Role
+		errs.AddIfErr(p.decode(fileContents, &role))
+		r := internalrbac.Role{Obj: role, Location: fileLocation}
+		s.roles = append(s.roles, r)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: role.TypeMeta, ObjectMeta: role.ObjectMeta, FileLocationer: r})
+	case rbacv1.SchemeGroupVersion.WithKind("ClusterRole"):
+		var role rbacv1.ClusterRole
+		errs.AddIfErr(p.decode(fileContents, &role))
+		r := internalrbac.ClusterRole{Obj: role, Location: fileLocation}
+		s.roles = append(s.roles, r)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: role.TypeMeta, ObjectMeta: role.ObjectMeta, FileLocationer: r})
+	case rbacv1.SchemeGroupVersion.WithKind("RoleBinding"):
+		var binding rbacv1.RoleBinding
+		errs.AddIfErr(p.decode(fileContents, &binding))
+		b := internalrbac.RoleBinding{Obj: binding, Location: fileLocation}
+		s.roleBindings = append(s.roleBindings, b)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: binding.TypeMeta, ObjectMeta: binding.ObjectMeta, FileLocationer: b})
+	case rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding"):
+		var binding rbacv1.ClusterRoleBinding
+		errs.AddIfErr(p.decode(fileContents, &binding))
+		b := internalrbac.ClusterRoleBinding{Obj: binding, Location: fileLocation}
+		s.roleBindings = append(s.roleBindings, b)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: binding.TypeMeta, ObjectMeta: binding.ObjectMeta, FileLocationer: b})
+
 	case policyv1beta1.SchemeGroupVersion.WithKind("PodDisruptionBudget"):
 		var disruptBudget policyv1beta1.PodDisruptionBudget
 		errs.AddIfErr(p.decode(fileContents, &disruptBudget))
@@ -409,14 +630,75 @@ func (p *Parser) decodeItem(cnf config.Configuration, s *parsedObjects, detected
 			FileLocationer: h,
 		})
 
+	case autoscalingv2.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"):
+		var hpa autoscalingv2.HorizontalPodAutoscaler
+		errs.AddIfErr(p.decode(fileContents, &hpa))
+		h := internal.HPAv2{HorizontalPodAutoscaler: hpa, Location: fileLocation}
+		s.hpaTargeters = append(s.hpaTargeters, h)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: hpa.TypeMeta, ObjectMeta: hpa.ObjectMeta, FileLocationer: h})
+
+	case internalgateway.V1.WithKind("Gateway"), internalgateway.V1beta1.WithKind("Gateway"), internalgateway.V1alpha2.WithKind("Gateway"):
+		var obj unstructured.Unstructured
+		errs.AddIfErr(p.decode(fileContents, &obj))
+		gw, err := internalgateway.NewGateway(obj, fileLocation)
+		errs.AddIfErr(err)
+		s.gateways = append(s.gateways, gw)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: gw.TypeMeta, ObjectMeta: gw.ObjectMeta, FileLocationer: gw})
+
+	case internalgateway.V1.WithKind("HTTPRoute"), internalgateway.V1beta1.WithKind("HTTPRoute"), internalgateway.V1alpha2.WithKind("HTTPRoute"):
+		var obj unstructured.Unstructured
+		errs.AddIfErr(p.decode(fileContents, &obj))
+		route, err := internalgateway.NewHTTPRoute(obj, fileLocation)
+		errs.AddIfErr(err)
+		s.routes = append(s.routes, route)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: route.TypeMeta, ObjectMeta: route.ObjectMeta, FileLocationer: route})
+
+	case internalgateway.V1.WithKind("GRPCRoute"), internalgateway.V1alpha2.WithKind("GRPCRoute"):
+		var obj unstructured.Unstructured
+		errs.AddIfErr(p.decode(fileContents, &obj))
+		route, err := internalgateway.NewGRPCRoute(obj, fileLocation)
+		errs.AddIfErr(err)
+		s.routes = append(s.routes, route)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: route.TypeMeta, ObjectMeta: route.ObjectMeta, FileLocationer: route})
+
 	default:
-		if cnf.VerboseOutput > 1 {
-			log.Printf("Unknown datatype: %s", detectedVersion.String())
+		// Documents without a type are not Kubernetes objects
+		if detectedVersion.Kind == "" || detectedVersion.Version == "" {
+			if cnf.VerboseOutput > 1 {
+				log.Printf("Unknown datatype: %s", detectedVersion.String())
+			}
+			break
 		}
+
+		// Objects of other kinds, such as custom resources, only have their metadata checked
+		var obj unstructured.Unstructured
+		errs.AddIfErr(p.decode(fileContents, &obj))
+		var objectMeta metav1.ObjectMeta
+		if metadata, ok := obj.Object["metadata"].(map[string]interface{}); ok {
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(metadata, &objectMeta); err != nil {
+				errs.AddIfErr(fmt.Errorf("Failed to parse metadata of %s: err=%w", detectedVersion, err))
+			}
+		}
+
+		// Kinds that have been configured to have a pod template are scored with all pod checks.
+		// Objects without a pod template only have their metadata checked.
+		if podTemplate, ok := findPodTemplate(cnf.PodTemplates, obj.GetAPIVersion(), obj.GetKind()); ok {
+			ps, found, err := internal.NewCustomPodSpecer(obj, objectMeta, podTemplate, fileLocation)
+			if found || err != nil {
+				errs.AddIfErr(err)
+				addPodSpeccer(ps)
+				break
+			}
+		}
+
+		u := internal.Unknown{Obj: obj, ObjectMeta: objectMeta, Location: fileLocation}
+		s.unknownObjects = append(s.unknownObjects, u)
+		s.bothMetas = append(s.bothMetas, ks.BothMeta{TypeMeta: u.GetTypeMeta(), ObjectMeta: objectMeta, FileLocationer: u})
 	}
 
 	if errs.Any() {
 		return errs
 	}
+
 	return nil
 }
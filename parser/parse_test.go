@@ -9,6 +9,7 @@ import (
 	ks "github.com/younes-bami/kube-score/domain"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func TestParse(t *testing.T) {
@@ -54,11 +55,30 @@ spec:
       labels:
         foo: bar`
 
-	fl := detectFileLocation("someName", 1, []byte(doc))
+	fl := detectFileLocation("someName", 1, []byte(doc), objectNode([]byte(doc)))
+	assert.Nil(t, fl.Fields)
 	assert.Equal(t, "app1/templates/deployment.yaml", fl.Name)
 	assert.Equal(t, 1, fl.Line)
 }
 
+func TestFileLocationHelmTemplate(t *testing.T) {
+	doc := `# Source: testdata/chart/templates/deployment.yaml
+# The main deployment
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+spec:
+  template:
+    metadata:
+      labels:
+        foo: bar`
+
+	fl := detectFileLocation("someName", 1, []byte(doc), objectNode([]byte(doc)))
+	assert.Equal(t, "testdata/chart/templates/deployment.yaml", fl.Name)
+	assert.Equal(t, 3, fl.Line)
+}
+
 func TestFileLocation(t *testing.T) {
 	doc := `kind: Deployment
 apiVersion: apps/v1
@@ -70,7 +90,242 @@ spec:
       labels:
         foo: bar`
 
-	fl := detectFileLocation("someName", 123, []byte(doc))
+	fl := detectFileLocation("someName", 123, []byte(doc), objectNode([]byte(doc)))
 	assert.Equal(t, "someName", fl.Name)
 	assert.Equal(t, 123, fl.Line)
+
+	assert.Equal(t, ks.FileLocation{Name: "someName", Line: 131, Column: 9}, fl.Fields.FieldLocation("spec.template.metadata.labels.foo"))
+	assert.Equal(t, ks.FileLocation{Name: "someName", Line: 128, Column: 3}, fl.Fields.FieldLocation("spec.template.spec.containers[0]"))
+}
+
+func TestParseLiveObjects(t *testing.T) {
+	parser, err := New()
+	assert.NoError(t, err)
+
+	fp, err := os.Open("testdata/live-objects.json")
+	assert.NoError(t, err)
+
+	parsed, err := parser.ParseFiles(config.Configuration{
+		AllFiles:    []ks.NamedReader{fp},
+		LiveObjects: true,
+	})
+	assert.NoError(t, err)
+
+	// The default "kubernetes" service is not scored
+	assert.Len(t, parsed.Services(), 0)
+
+	// The pod managed by a ReplicaSet is not scored
+	assert.Len(t, parsed.Pods(), 1)
+	pod := parsed.Pods()[0].Pod()
+	assert.Equal(t, "standalone", pod.Name)
+	assert.Len(t, pod.Spec.Volumes, 1)
+	assert.Equal(t, "data", pod.Spec.Volumes[0].Name)
+	assert.Len(t, pod.Spec.Containers[0].VolumeMounts, 1)
+
+	// Fields that are not set to their default value are kept
+	assert.Equal(t, corev1.PullAlways, pod.Spec.Containers[0].ImagePullPolicy)
+	assert.Equal(t, "/tmp/termination-log", pod.Spec.Containers[0].TerminationMessagePath)
+	assert.Equal(t, corev1.RestartPolicyNever, pod.Spec.RestartPolicy)
+	assert.Empty(t, pod.Spec.DNSPolicy)
+
+	assert.Len(t, parsed.Deployments(), 1)
+	deployment := parsed.Deployments()[0].Deployment()
+	assert.Equal(t, map[string]string{"team": "foo"}, deployment.Annotations)
+	assert.Empty(t, deployment.ManagedFields)
+	assert.Empty(t, deployment.ResourceVersion)
+	assert.Empty(t, deployment.Status.Replicas)
+
+	// Fields that are set to their default value by the server are removed
+	assert.Nil(t, deployment.Spec.ProgressDeadlineSeconds)
+	assert.Equal(t, int32(5), *deployment.Spec.RevisionHistoryLimit)
+	podSpec := deployment.Spec.Template.Spec
+	assert.Empty(t, podSpec.DNSPolicy)
+	assert.Empty(t, podSpec.RestartPolicy)
+	assert.Empty(t, podSpec.SchedulerName)
+	assert.Nil(t, podSpec.SecurityContext)
+	assert.Empty(t, podSpec.Containers[0].ImagePullPolicy)
+	assert.Empty(t, podSpec.Containers[0].TerminationMessagePath)
+	assert.Empty(t, podSpec.Containers[0].TerminationMessagePolicy)
+}
+
+func TestParseLiveObjectsDisabled(t *testing.T) {
+	parser, err := New()
+	assert.NoError(t, err)
+
+	fp, err := os.Open("testdata/live-objects.json")
+	assert.NoError(t, err)
+
+	parsed, err := parser.ParseFiles(config.Configuration{
+		AllFiles: []ks.NamedReader{fp},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, parsed.Services(), 1)
+	assert.Len(t, parsed.Pods(), 2)
+}
+
+func TestParseCustomPodTemplates(t *testing.T) {
+	parser, err := New()
+	assert.NoError(t, err)
+
+	fp, err := os.Open("testdata/custom-pod-templates.yaml")
+	assert.NoError(t, err)
+
+	parsed, err := parser.ParseFiles(config.Configuration{
+		AllFiles: []ks.NamedReader{fp},
+		PodTemplates: []config.PodTemplate{
+			{APIVersion: "argoproj.io/v1alpha1", Kind: "Rollout", Template: "spec.template", Replicas: "spec.replicas", Selector: "spec.selector"},
+			{APIVersion: "apps.openshift.io/v1", Kind: "DeploymentConfig", Template: "spec.template", Replicas: "spec.replicas", Selector: "spec.selector"},
+			{APIVersion: "serving.knative.dev/v1", Kind: "Service", Template: "spec.template"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, parsed.UnknownObjects())
+	assert.Len(t, parsed.Metas(), 3)
+
+	podSpecers := parsed.PodSpeccers()
+	assert.Len(t, podSpecers, 3)
+
+	rollout := podSpecers[0]
+	assert.Equal(t, "Rollout", rollout.GetTypeMeta().Kind)
+	assert.Equal(t, "prod", rollout.GetPodTemplateSpec().Namespace)
+	assert.Equal(t, "app:1.0.0", rollout.GetPodTemplateSpec().Spec.Containers[0].Image)
+	assert.Equal(t, int32(5), *rollout.(ks.ReplicasGetter).GetReplicas())
+	assert.Equal(t, map[string]string{"app": "rollout"}, rollout.(ks.SelectorGetter).GetSelector().MatchLabels)
+	assert.Equal(t, "spec.template", rollout.(ks.PodTemplatePather).PodTemplatePath())
+
+	// Selectors can also be a map of labels
+	deploymentConfig := podSpecers[1]
+	assert.Nil(t, deploymentConfig.(ks.ReplicasGetter).GetReplicas())
+	assert.Equal(t, map[string]string{"app": "deploymentconfig"}, deploymentConfig.(ks.SelectorGetter).GetSelector().MatchLabels)
+
+	knative := podSpecers[2]
+	assert.Equal(t, "app:1.0.0", knative.GetPodTemplateSpec().Spec.Containers[0].Image)
+	assert.Nil(t, knative.(ks.SelectorGetter).GetSelector())
+}
+
+func TestParseCustomPodTemplateMissing(t *testing.T) {
+	parser, err := New()
+	assert.NoError(t, err)
+
+	fp, err := os.Open("testdata/custom-pod-template-workloadref.yaml")
+	assert.NoError(t, err)
+
+	// Objects without a pod template, such as Rollouts that use workloadRef, only have their metadata checked
+	parsed, err := parser.ParseFiles(config.Configuration{
+		AllFiles: []ks.NamedReader{fp},
+		PodTemplates: []config.PodTemplate{
+			{APIVersion: "argoproj.io/v1alpha1", Kind: "Rollout", Template: "spec.template"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, parsed.PodSpeccers())
+	assert.Len(t, parsed.UnknownObjects(), 1)
+	assert.Len(t, parsed.Metas(), 1)
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	parser, err := New()
+	assert.NoError(t, err)
+
+	fp, err := os.Open("testdata/schema-errors.yaml")
+	assert.NoError(t, err)
+
+	parsed, err := parser.ParseFiles(config.Configuration{
+		AllFiles: []ks.NamedReader{fp},
+	})
+	assert.NoError(t, err)
+
+	metas := parsed.Metas()
+	assert.Len(t, metas, 2)
+	assert.Empty(t, metas[0].SchemaErrors)
+	assert.Equal(t, []ks.SchemaError{
+		{Type: ks.SchemaErrorDuplicateField, FieldPath: "spec.template.spec.containers[0].image", Message: "Duplicate field spec.template.spec.containers[0].image"},
+		{Type: ks.SchemaErrorUnknownField, FieldPath: "spec.template.spec.containers[0].livenessprobe", Message: "Unknown field spec.template.spec.containers[0].livenessprobe"},
+	}, metas[1].SchemaErrors)
+}
+
+func TestParseSchemaErrorsWrongType(t *testing.T) {
+	parser, err := New()
+	assert.NoError(t, err)
+
+	fp, err := os.Open("testdata/schema-wrong-type.yaml")
+	assert.NoError(t, err)
+
+	parsed, err := parser.ParseFiles(config.Configuration{
+		AllFiles:       []ks.NamedReader{fp},
+		StrictDecoding: true,
+	})
+	assert.NoError(t, err)
+
+	metas := parsed.Metas()
+	assert.Len(t, metas, 1)
+	assert.Len(t, metas[0].SchemaErrors, 4)
+	assert.Equal(t, ks.SchemaError{Type: ks.SchemaErrorWrongType, FieldPath: "metadata.labels.enabled", Message: "Field metadata.labels.enabled has the wrong type, expected a string"}, metas[0].SchemaErrors[0])
+	assert.Equal(t, ks.SchemaError{Type: ks.SchemaErrorWrongType, FieldPath: "spec.replicas", Message: "Field spec.replicas has the wrong type, expected an integer"}, metas[0].SchemaErrors[1])
+	assert.Equal(t, "spec.template.spec.containers[0].ports[0].containerPort", metas[0].SchemaErrors[2].FieldPath)
+	assert.Equal(t, "spec.template.spec.containers[0].resources.limits.cpu", metas[0].SchemaErrors[3].FieldPath)
+
+	// The fields with the wrong type are not decoded, while the rest of the object is
+	deployments := parsed.Deployments()
+	assert.Len(t, deployments, 1)
+	deployment := deployments[0].Deployment()
+	assert.Empty(t, deployment.Labels)
+	assert.Nil(t, deployment.Spec.Replicas)
+	assert.True(t, deployment.Spec.Template.Spec.HostNetwork)
+	container := deployment.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, int32(0), container.Ports[0].ContainerPort)
+	assert.Equal(t, "128Mi", container.Resources.Limits.Memory().String())
+	assert.True(t, container.Resources.Limits.Cpu().IsZero())
+
+	// The location of the fields is in the original file
+	assert.Equal(t, 8, metas[0].FileLocationer.FileLocation().Fields.FieldLocation("spec.replicas").Line)
+}
+
+func TestParseSchemaErrorsWrongTypeNotStrict(t *testing.T) {
+	parser, err := New()
+	assert.NoError(t, err)
+
+	fp, err := os.Open("testdata/schema-wrong-type.yaml")
+	assert.NoError(t, err)
+
+	_, err = parser.ParseFiles(config.Configuration{
+		AllFiles: []ks.NamedReader{fp},
+	})
+	assert.Error(t, err)
+}
+
+func TestParseGatewayAPI(t *testing.T) {
+	parser, err := New()
+	assert.NoError(t, err)
+
+	fp, err := os.Open("testdata/gateway-api.yaml")
+	assert.NoError(t, err)
+
+	parsed, err := parser.ParseFiles(config.Configuration{
+		AllFiles: []ks.NamedReader{fp},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, parsed.UnknownObjects())
+	assert.Len(t, parsed.Metas(), 3)
+
+	assert.Len(t, parsed.Gateways(), 1)
+	listeners := parsed.Gateways()[0].GatewaySpec().Listeners
+	assert.Len(t, listeners, 1)
+	assert.Equal(t, "*.example.com", *listeners[0].Hostname)
+	assert.Equal(t, int32(443), listeners[0].Port)
+
+	routes := parsed.Routes()
+	assert.Len(t, routes, 2)
+	match := routes[0].RouteSpec().Rules[0].Matches[0]
+	assert.Equal(t, "/api", *match.Path.Value)
+	assert.Equal(t, "GET", *match.Method)
+	assert.Equal(t, "infra", *routes[0].RouteSpec().ParentRefs[0].Namespace)
+
+	grpc := routes[1]
+	assert.Equal(t, "GRPCRoute", grpc.GetTypeMeta().Kind)
+	method := grpc.RouteSpec().Rules[0].Matches[0].GRPCMethod
+	assert.Equal(t, "example.Greeter", *method.Service)
+	assert.Equal(t, "SayHello", *method.Method)
+	assert.Nil(t, grpc.RouteSpec().Rules[0].Matches[0].Method)
+	assert.Equal(t, int32(8080), *grpc.RouteSpec().Rules[0].BackendRefs[0].Port)
 }
@@ -0,0 +1,296 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+)
+
+// validateSchema returns the fields of an object that are unknown, set more than once, or have the wrong type.
+//
+// The fields are validated against the Go types that kube-score is built with, and not against the OpenAPI schema of a
+// specific Kubernetes version. Only kinds that are registered in the scheme are validated.
+//
+// Fields with the wrong type make the object fail to decode. If cnf.StrictDecoding is set, these fields are removed
+// from raw before it is decoded, so that they are reported as schema errors instead. The returned raw is then the
+// sanitized object.
+func (p *Parser) validateSchema(cnf config.Configuration, gvk schema.GroupVersionKind, raw []byte, node *yaml.Node) ([]ks.SchemaError, []byte, error) {
+	obj, err := p.scheme.New(gvk)
+	if err != nil {
+		return nil, raw, nil
+	}
+	objType := reflect.TypeOf(obj).Elem()
+
+	if node == nil {
+		node = objectNode(raw)
+		if node == nil {
+			return nil, raw, nil
+		}
+	}
+
+	v := schemaValidator{}
+	v.validate("", node, objType)
+	if !cnf.StrictDecoding || len(v.removals) == 0 {
+		return v.errs, raw, nil
+	}
+
+	// node is used to find the location of fields, and is not modified. The object is parsed again to be sanitized.
+	sanitized := objectNode(raw)
+	if sanitized == nil {
+		return v.errs, raw, nil
+	}
+	sv := schemaValidator{}
+	sv.validate("", sanitized, objType)
+	for i := len(sv.removals) - 1; i >= 0; i-- {
+		sv.removals[i].apply()
+	}
+	res, err := yaml.Marshal(sanitized)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v.errs, res, nil
+}
+
+// schemaValidator walks a YAML node tree, and compares it to a Go type
+type schemaValidator struct {
+	errs []ks.SchemaError
+
+	// removals are the fields with the wrong type, in the order that they were found
+	removals []schemaRemoval
+}
+
+// schemaRemoval is a field with the wrong type, that is removed from its parent
+type schemaRemoval struct {
+	parent *yaml.Node
+	index  int
+}
+
+func (r schemaRemoval) apply() {
+	n := 1
+	if r.parent.Kind == yaml.MappingNode {
+		n = 2 // The key and the value
+	}
+	r.parent.Content = append(r.parent.Content[:r.index], r.parent.Content[r.index+n:]...)
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// validate validates node against t. It returns false if the node has the wrong type.
+func (v *schemaValidator) validate(path string, node *yaml.Node, t reflect.Type) bool {
+	node = resolveAlias(node)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return true
+	}
+
+	// Types such as resource.Quantity and intstr.IntOrString decode themselves
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return v.validateUnmarshaler(path, node, t)
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return v.wrongType(path, "an object")
+		}
+		v.validateStruct(path, node, t)
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return v.wrongType(path, "an object")
+		}
+		v.validateMapping(path, node, func(key string) (reflect.Type, bool) {
+			return t.Elem(), true
+		})
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded as a base64 string
+			return v.validateScalar(path, node, "a string", isString)
+		}
+		if node.Kind != yaml.SequenceNode {
+			return v.wrongType(path, "a list")
+		}
+		for i := 0; i < len(node.Content); i++ {
+			if !v.validate(fmt.Sprintf("%s[%d]", path, i), node.Content[i], t.Elem()) {
+				v.removals = append(v.removals, schemaRemoval{parent: node, index: i})
+			}
+		}
+	case reflect.String:
+		return v.validateScalar(path, node, "a string", isString)
+	case reflect.Bool:
+		return v.validateScalar(path, node, "a boolean", isBool)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.validateScalar(path, node, "an integer", func(n *yaml.Node) bool { return n.Tag == "!!int" })
+	case reflect.Float32, reflect.Float64:
+		return v.validateScalar(path, node, "a number", func(n *yaml.Node) bool { return n.Tag == "!!int" || n.Tag == "!!float" })
+	}
+	return true
+}
+
+// validateStruct validates the fields of a mapping against the JSON fields of a struct
+func (v *schemaValidator) validateStruct(path string, node *yaml.Node, t reflect.Type) {
+	fields := map[string]reflect.Type{}
+	jsonFields(t, fields)
+
+	v.validateMapping(path, node, func(key string) (reflect.Type, bool) {
+		fieldType, ok := fields[key]
+		return fieldType, ok
+	})
+}
+
+// validateMapping validates the values of a mapping. fieldType returns the type of a key, or false if the key is unknown.
+func (v *schemaValidator) validateMapping(path string, node *yaml.Node, fieldType func(key string) (reflect.Type, bool)) {
+	seen := map[string]struct{}{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if key == "<<" && node.Content[i].Tag == "!!merge" {
+			continue
+		}
+		fieldPath := joinFieldPath(path, key)
+
+		if _, ok := seen[key]; ok {
+			v.errs = append(v.errs, ks.SchemaError{
+				Type:      ks.SchemaErrorDuplicateField,
+				FieldPath: fieldPath,
+				Message:   fmt.Sprintf("Duplicate field %s", fieldPath),
+			})
+		}
+		seen[key] = struct{}{}
+
+		t, ok := fieldType(key)
+		if !ok {
+			v.errs = append(v.errs, ks.SchemaError{
+				Type:      ks.SchemaErrorUnknownField,
+				FieldPath: fieldPath,
+				Message:   fmt.Sprintf("Unknown field %s", fieldPath),
+			})
+			continue
+		}
+		if !v.validate(fieldPath, node.Content[i+1], t) {
+			v.removals = append(v.removals, schemaRemoval{parent: node, index: i})
+		}
+	}
+}
+
+func (v *schemaValidator) validateScalar(path string, node *yaml.Node, expected string, valid func(*yaml.Node) bool) bool {
+	if node.Kind != yaml.ScalarNode || !valid(node) {
+		return v.wrongType(path, expected)
+	}
+	return true
+}
+
+func (v *schemaValidator) validateUnmarshaler(path string, node *yaml.Node, t reflect.Type) bool {
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return v.invalidValue(path, err)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return v.invalidValue(path, err)
+	}
+	if err := reflect.New(t).Interface().(json.Unmarshaler).UnmarshalJSON(data); err != nil {
+		return v.invalidValue(path, err)
+	}
+	return true
+}
+
+func (v *schemaValidator) wrongType(path, expected string) bool {
+	v.errs = append(v.errs, ks.SchemaError{
+		Type:      ks.SchemaErrorWrongType,
+		FieldPath: path,
+		Message:   fmt.Sprintf("Field %s has the wrong type, expected %s", path, expected),
+	})
+	return false
+}
+
+func (v *schemaValidator) invalidValue(path string, err error) bool {
+	v.errs = append(v.errs, ks.SchemaError{
+		Type:      ks.SchemaErrorWrongType,
+		FieldPath: path,
+		Message:   fmt.Sprintf("Field %s has an invalid value: %s", path, err),
+	})
+	return false
+}
+
+// plainBools are the plain scalars that are booleans in YAML 1.1. The decoder of Kubernetes uses YAML 1.1, while
+// gopkg.in/yaml.v3 only resolves true and false as booleans.
+var plainBools = map[string]struct{}{
+	"y": {}, "Y": {}, "yes": {}, "Yes": {}, "YES": {},
+	"n": {}, "N": {}, "no": {}, "No": {}, "NO": {},
+	"on": {}, "On": {}, "ON": {},
+	"off": {}, "Off": {}, "OFF": {},
+}
+
+func isPlainBool(n *yaml.Node) bool {
+	if n.Style != 0 || n.Tag != "!!str" {
+		return false
+	}
+	_, ok := plainBools[n.Value]
+	return ok
+}
+
+func isString(n *yaml.Node) bool {
+	switch n.Tag {
+	case "!!str":
+		return !isPlainBool(n)
+	case "!!timestamp", "!!binary":
+		return true
+	}
+	return false
+}
+
+func isBool(n *yaml.Node) bool {
+	return n.Tag == "!!bool" || isPlainBool(n)
+}
+
+// jsonFields adds the JSON fields of a struct to fields, including the fields of inlined structs
+func jsonFields(t reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" && f.Anonymous {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				jsonFields(embedded, fields)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+}
+
+// joinFieldPath adds a key to a field path. Keys that contain dots or brackets are quoted.
+func joinFieldPath(path, key string) string {
+	if strings.ContainsAny(key, ".[]\"") {
+		return path + "[" + strconv.Quote(key) + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
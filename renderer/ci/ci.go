@@ -44,6 +44,9 @@ func CI(scoreCard *scorecard.Scorecard) io.Reader {
 				if comment.Path != "" {
 					message = "(" + comment.Path + ") " + comment.Summary
 				}
+				if comment.FileLocation.Line > 0 {
+					message += fmt.Sprintf(" (%s:%d:%d)", comment.FileLocation.Name, comment.FileLocation.Line, comment.FileLocation.Column)
+				}
 
 				if card.Skipped {
 					fmt.Fprintf(w, "[SKIPPED] %s: %s\n",
@@ -109,3 +109,30 @@ func TestCiOutput(t *testing.T) {
 [SKIPPED] bar-no-namespace v1/Testing
 `, string(all))
 }
+
+func TestCiOutputFieldLocation(t *testing.T) {
+	t.Parallel()
+	card := &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:   v1.TypeMeta{Kind: "Testing", APIVersion: "v1"},
+			ObjectMeta: v1.ObjectMeta{Name: "foo"},
+			Checks: []scorecard.TestScore{
+				{
+					Check: domain.Check{Name: "test-critical"},
+					Grade: scorecard.GradeCritical,
+					Comments: []scorecard.TestScoreComment{
+						{
+							Path:         "a",
+							Summary:      "summary",
+							FieldPath:    "spec.containers[0].image",
+							FileLocation: domain.FileLocation{Name: "pod.yaml", Line: 12, Column: 7},
+						},
+					},
+				},
+			},
+		},
+	}
+	all, err := ioutil.ReadAll(CI(card))
+	assert.Nil(t, err)
+	assert.Equal(t, "[CRITICAL] foo v1/Testing: (a) summary (pod.yaml:12:7)\n", string(all))
+}
@@ -122,6 +122,11 @@ func outputHumanStep(card scorecard.TestScore, verboseOutput int, termWidth int)
 			fmt.Fprint(w, wordwrap.Indent(wrapped, strings.Repeat(" ", 12), false))
 		}
 
+		if comment.FileLocation.Line > 0 {
+			fmt.Fprintln(w)
+			fmt.Fprintf(w, "%sLocation: %s:%d:%d", strings.Repeat(" ", 12), comment.FileLocation.Name, comment.FileLocation.Line, comment.FileLocation.Column)
+		}
+
 		if len(comment.DocumentationURL) > 0 {
 			fmt.Fprintln(w)
 			fmt.Fprintf(w, "%sMore information: %s", strings.Repeat(" ", 12), comment.DocumentationURL)
@@ -428,3 +428,38 @@ func TestHumanOutputWithLongObjectNames(t *testing.T) {
             nisl venenatis, elementum augue a, porttitor libero.
 `, string(all))
 }
+
+func TestHumanOutputFieldLocation(t *testing.T) {
+	t.Parallel()
+	card := &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:   v1.TypeMeta{Kind: "Testing", APIVersion: "v1"},
+			ObjectMeta: v1.ObjectMeta{Name: "foo"},
+			Checks: []scorecard.TestScore{
+				{
+					Check: domain.Check{Name: "test-critical"},
+					Grade: scorecard.GradeCritical,
+					Comments: []scorecard.TestScoreComment{
+						{
+							Path:         "a",
+							Summary:      "summary",
+							Description:  "description",
+							FieldPath:    "spec.containers[0].image",
+							FileLocation: domain.FileLocation{Name: "pod.yaml", Line: 12, Column: 7},
+						},
+					},
+				},
+			},
+		},
+	}
+	r, err := Human(card, 0, 100, false)
+	assert.Nil(t, err)
+	all, err := ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, `v1/Testing foo                                                                💥
+    [CRITICAL] test-critical
+        · a -> summary
+            description
+            Location: pod.yaml:12:7
+`, string(all))
+}
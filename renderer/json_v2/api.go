@@ -39,6 +39,9 @@ type TestScoreComment struct {
 	Path        string `json:"path"`
 	Summary     string `json:"summary"`
 	Description string `json:"description"`
+	FieldPath   string `json:"field_path,omitempty"`
+	FileRow     int    `json:"file_row,omitempty"`
+	FileColumn  int    `json:"file_column,omitempty"`
 }
 
 func Output(input *scorecard.Scorecard) io.Reader {
@@ -80,6 +83,9 @@ func convertComments(in []scorecard.TestScoreComment) (res []TestScoreComment) {
 			Path:        v.Path,
 			Summary:     v.Summary,
 			Description: v.Description,
+			FieldPath:   v.FieldPath,
+			FileRow:     v.FileLocation.Line,
+			FileColumn:  v.FileLocation.Column,
 		})
 	}
 	return
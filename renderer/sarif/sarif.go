@@ -46,6 +46,7 @@ func Output(input *scorecard.Scorecard) io.Reader {
 			addRule(check.Check)
 
 			for _, comment := range check.Comments {
+				location := comment.Location(v.FileLocation)
 				results = append(results, sarif.Results{
 					Message: sarif.Message{
 						Text: comment.Summary,
@@ -60,7 +61,11 @@ func Output(input *scorecard.Scorecard) io.Reader {
 						{
 							PhysicalLocation: sarif.PhysicalLocation{
 								ArtifactLocation: sarif.ArtifactLocation{
-									URI: "file://" + v.FileLocation.Name,
+									URI: "file://" + location.Name,
+								},
+								Region: sarif.Region{
+									StartLine:   location.Line,
+									StartColumn: location.Column,
 								},
 								ContextRegion: sarif.ContextRegion{
 									StartLine: v.FileLocation.Line,
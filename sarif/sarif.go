@@ -60,8 +60,9 @@ type Snippet struct {
 }
 
 type Region struct {
-	Snippet   Snippet `json:"snippet,omitempty"`
-	StartLine int     `json:"startLine,omitempty"`
+	Snippet     Snippet `json:"snippet,omitempty"`
+	StartLine   int     `json:"startLine,omitempty"`
+	StartColumn int     `json:"startColumn,omitempty"`
 }
 
 type ArtifactLocation struct {
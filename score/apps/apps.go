@@ -23,6 +23,9 @@ func Register(allChecks *checks.Checks, allHPAs []ks.HpaTargeter, allServices []
 
 	allChecks.RegisterDeploymentCheck("Deployment Pod Selector labels match template metadata labels", "Ensure the StatefulSet selector labels match the template metadata labels.", deploymentSelectorLabelsMatching)
 	allChecks.RegisterStatefulSetCheck("StatefulSet Pod Selector labels match template metadata labels", "Ensure the StatefulSet selector labels match the template metadata labels.", statefulSetSelectorLabelsMatching)
+
+	allChecks.RegisterWorkloadCheck("Workload targeted by HPA does not have replicas configured", "Makes sure that ReplicaSets, ReplicationControllers and custom workloads using a HorizontalPodAutoscaler don't have a statically configured replica count set", hpaWorkloadNoReplicas(allHPAs))
+	allChecks.RegisterWorkloadCheck("Workload Pod Selector labels match template metadata labels", "Ensure the selector labels of ReplicaSets, ReplicationControllers and custom workloads match the template metadata labels.", workloadSelectorLabelsMatching)
 }
 
 func hpaDeploymentNoReplicas(allHPAs []ks.HpaTargeter) func(deployment appsv1.Deployment) (scorecard.TestScore, error) {
@@ -53,6 +56,35 @@ func hpaDeploymentNoReplicas(allHPAs []ks.HpaTargeter) func(deployment appsv1.De
 	}
 }
 
+// hpaWorkloadNoReplicas is hpaDeploymentNoReplicas for workloads
+func hpaWorkloadNoReplicas(allHPAs []ks.HpaTargeter) func(ks.Workload) (scorecard.TestScore, error) {
+	return func(workload ks.Workload) (score scorecard.TestScore, err error) {
+		meta := workload.GetObjectMeta()
+		for _, hpa := range allHPAs {
+			target := hpa.HpaTarget()
+
+			if hpa.GetObjectMeta().Namespace == meta.Namespace &&
+				strings.EqualFold(target.Kind, workload.GetTypeMeta().Kind) &&
+				target.Name == meta.Name {
+
+				if workload.GetReplicas() == nil {
+					score.Grade = scorecard.GradeAllOK
+					return
+				}
+
+				score.Grade = scorecard.GradeCritical
+				score.AddComment("", fmt.Sprintf("The %s is targeted by a HPA, but a static replica count is configured", workload.GetTypeMeta().Kind), "When replicas are both statically set and managed by the HPA, the replicas will be changed to the statically configured count when the spec is applied, even if the HPA wants the replica count to be higher.")
+				return
+			}
+		}
+
+		score.Grade = scorecard.GradeAllOK
+		score.Skipped = true
+		score.AddComment("", fmt.Sprintf("Skipped because the %s is not targeted by a HorizontalPodAutoscaler", workload.GetTypeMeta().Kind), "")
+		return
+	}
+}
+
 func deploymentHasAntiAffinity(deployment appsv1.Deployment) (score scorecard.TestScore, err error) {
 	// Ignore if the deployment only has a single replica
 	// If replicas is not explicitly set, we'll still warn if the anti affinity is missing
@@ -210,3 +242,30 @@ func deploymentSelectorLabelsMatching(deployment appsv1.Deployment) (score score
 	score.AddComment("", "Deployment selector labels not matching template metadata labels", "Deployment require `.spec.selector` to match `.spec.template.metadata.labels`. https://kubernetes.io/docs/concepts/workloads/controllers/deployment/")
 	return
 }
+
+// workloadSelectorLabelsMatching checks that the selector of the workload matches the labels of its pod template.
+// Workloads without a selector, such as ReplicationControllers that default to the labels of the template, are skipped.
+func workloadSelectorLabelsMatching(workload ks.Workload) (score scorecard.TestScore, err error) {
+	kind := workload.GetTypeMeta().Kind
+	if workload.GetSelector() == nil {
+		score.Skipped = true
+		score.AddComment("", fmt.Sprintf("Skipped because the %s does not have a selector", kind), "")
+		return
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(workload.GetSelector())
+	if err != nil {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", fmt.Sprintf("%s selector labels are not matching template metadata labels", kind), fmt.Sprintf("Invalid selector: %s", err))
+		return score, nil
+	}
+
+	if selector.Matches(internal.MapLabels(workload.GetPodTemplateSpec().Labels)) {
+		score.Grade = scorecard.GradeAllOK
+		return
+	}
+
+	score.Grade = scorecard.GradeCritical
+	score.AddComment("", fmt.Sprintf("%s selector labels not matching template metadata labels", kind), fmt.Sprintf("The selector of the %s must match the labels of its pod template, or it does not manage any pods.", kind))
+	return
+}
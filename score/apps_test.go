@@ -76,6 +76,11 @@ func TestDeploymentWithHPANotHasReplicas(t *testing.T) {
 	testExpectedScore(t, "deployment-with-hpa-not-has-replicas.yaml", "Deployment targeted by HPA does not have replicas configured", scorecard.GradeAllOK)
 }
 
+func TestDeploymentWithHPAv2HasReplicas(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "deployment-with-hpa-v2-has-replicas.yaml", "Deployment targeted by HPA does not have replicas configured", scorecard.GradeCritical)
+}
+
 func TestStatefulsetHasServiceName(t *testing.T) {
 	t.Parallel()
 	testExpectedScore(t, "statefulset-service-name.yaml", "StatefulSet has ServiceName", scorecard.GradeAllOK)
@@ -26,6 +26,11 @@ func New(cnf config.Configuration) *Checks {
 		cronjobs:                 make(map[string]GenCheck[ks.CronJob]),
 		horizontalPodAutoscalers: make(map[string]GenCheck[ks.HpaTargeter]),
 		poddisruptionbudgets:     make(map[string]GenCheck[ks.PodDisruptionBudget]),
+		routes:                   make(map[string]GenCheck[ks.Route]),
+		roles:                    make(map[string]GenCheck[ks.Role]),
+		roleBindings:             make(map[string]GenCheck[ks.RoleBinding]),
+		configMaps:               make(map[string]GenCheck[ks.ConfigMap]),
+		workloads:                make(map[string]GenCheck[ks.Workload]),
 	}
 }
 
@@ -70,6 +75,11 @@ type Checks struct {
 	cronjobs                 map[string]GenCheck[ks.CronJob]
 	horizontalPodAutoscalers map[string]GenCheck[ks.HpaTargeter]
 	poddisruptionbudgets     map[string]GenCheck[ks.PodDisruptionBudget]
+	routes                   map[string]GenCheck[ks.Route]
+	roles                    map[string]GenCheck[ks.Role]
+	roleBindings             map[string]GenCheck[ks.RoleBinding]
+	configMaps               map[string]GenCheck[ks.ConfigMap]
+	workloads                map[string]GenCheck[ks.Workload]
 
 	cnf config.Configuration
 }
@@ -205,6 +215,72 @@ func (c *Checks) Services() map[string]GenCheck[corev1.Service] {
 	return c.services
 }
 
+func (c *Checks) RegisterRouteCheck(name, comment string, fn CheckFunc[ks.Route]) {
+	reg(c, "Route", name, comment, false, fn, c.routes)
+}
+
+func (c *Checks) RegisterOptionalRouteCheck(name, comment string, fn CheckFunc[ks.Route]) {
+	reg(c, "Route", name, comment, true, fn, c.routes)
+}
+
+func (c *Checks) Routes() map[string]GenCheck[ks.Route] {
+	return c.routes
+}
+
+func (c *Checks) RegisterRoleCheck(name, comment string, fn CheckFunc[ks.Role]) {
+	reg(c, "Role", name, comment, false, fn, c.roles)
+}
+
+func (c *Checks) RegisterOptionalRoleCheck(name, comment string, fn CheckFunc[ks.Role]) {
+	reg(c, "Role", name, comment, true, fn, c.roles)
+}
+
+func (c *Checks) Roles() map[string]GenCheck[ks.Role] {
+	return c.roles
+}
+
+func (c *Checks) RegisterRoleBindingCheck(name, comment string, fn CheckFunc[ks.RoleBinding]) {
+	reg(c, "RoleBinding", name, comment, false, fn, c.roleBindings)
+}
+
+func (c *Checks) RegisterOptionalRoleBindingCheck(name, comment string, fn CheckFunc[ks.RoleBinding]) {
+	reg(c, "RoleBinding", name, comment, true, fn, c.roleBindings)
+}
+
+func (c *Checks) RoleBindings() map[string]GenCheck[ks.RoleBinding] {
+	return c.roleBindings
+}
+
+func (c *Checks) RegisterConfigMapCheck(name, comment string, fn CheckFunc[ks.ConfigMap]) {
+	reg(c, "ConfigMap", name, comment, false, fn, c.configMaps)
+}
+
+func (c *Checks) RegisterOptionalConfigMapCheck(name, comment string, fn CheckFunc[ks.ConfigMap]) {
+	reg(c, "ConfigMap", name, comment, true, fn, c.configMaps)
+}
+
+func (c *Checks) ConfigMaps() map[string]GenCheck[ks.ConfigMap] {
+	return c.configMaps
+}
+
+func (c *Checks) RegisterWorkloadCheck(name, comment string, fn CheckFunc[ks.Workload]) {
+	reg(c, "Workload", name, comment, false, fn, c.workloads)
+}
+
+func (c *Checks) RegisterOptionalWorkloadCheck(name, comment string, fn CheckFunc[ks.Workload]) {
+	reg(c, "Workload", name, comment, true, fn, c.workloads)
+}
+
+func (c *Checks) Workloads() map[string]GenCheck[ks.Workload] {
+	return c.workloads
+}
+
+// RegisterPseudoCheck registers a check that is not run against objects, but is reported by the scorer directly.
+// It is only used to list the check together with all other checks.
+func (c *Checks) RegisterPseudoCheck(check ks.Check) {
+	c.all = append(c.all, check)
+}
+
 func (c *Checks) All() []ks.Check {
 	return c.all
 }
@@ -2,21 +2,25 @@ package container
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/younes-bami/kube-score/config"
 	ks "github.com/younes-bami/kube-score/domain"
 	"github.com/younes-bami/kube-score/score/checks"
+	"github.com/younes-bami/kube-score/score/internal"
 	"github.com/younes-bami/kube-score/scorecard"
 	corev1 "k8s.io/api/core/v1"
 )
 
 func Register(allChecks *checks.Checks, cnf config.Configuration) {
 	allChecks.RegisterPodCheck("Container Resources", `Makes sure that all pods have resource limits and requests set. The --ignore-container-cpu-limit flag can be used to disable the requirement of having a CPU limit`, containerResources(!cnf.IgnoreContainerCpuLimitRequirement, !cnf.IgnoreContainerMemoryLimitRequirement))
+	allChecks.RegisterPodCheck("Container Resource Limits Above Requests", `Makes sure that no resource limit is lower than the request of the same resource`, containerResourceLimitsAboveRequests)
+	allChecks.RegisterPodCheck("Container Resource Units", `Makes sure that CPU and memory quantities have plausible units, such as memory without a unit (bytes), decimal memory units such as G instead of Gi, and CPU without a unit (cores) above 64 cores`, containerResourceUnits)
+	allChecks.RegisterPodCheck("Container Resource Bounds", `Makes sure that CPU and memory requests, and the ratio between limits and requests, are within the bounds in the resource-bounds of the configuration file`, containerResourceBounds(cnf.ResourceBounds))
 	allChecks.RegisterOptionalPodCheck("Container Resource Requests Equal Limits", `Makes sure that all pods have the same requests as limits on resources set.`, containerResourceRequestsEqualLimits)
 	allChecks.RegisterOptionalPodCheck("Container CPU Requests Equal Limits", `Makes sure that all pods have the same CPU requests as limits set.`, containerCPURequestsEqualLimits)
 	allChecks.RegisterOptionalPodCheck("Container Memory Requests Equal Limits", `Makes sure that all pods have the same memory requests as limits set.`, containerMemoryRequestsEqualLimits)
 	allChecks.RegisterPodCheck("Container Image Tag", `Makes sure that a explicit non-latest tag is used`, containerImageTag)
+	allChecks.RegisterPodCheck("Container Image Policy", `Makes sure that images are pulled from an allowed registry, are pinned by digest, and do not use a forbidden tag, if configured with --allow-image-registry, --require-image-digest and --forbid-image-tag. Also makes sure that images are valid references.`, containerImagePolicy(cnf))
 	allChecks.RegisterPodCheck("Container Image Pull Policy", `Makes sure that the pullPolicy is set to Always. This makes sure that imagePullSecrets are always validated.`, containerImagePullPolicy)
 	allChecks.RegisterPodCheck("Container Ephemeral Storage Request and Limit", "Makes sure all pods have ephemeral-storage requests and limits set", containerStorageEphemeralRequestAndLimit)
 	allChecks.RegisterOptionalPodCheck("Container Ephemeral Storage Request Equals Limit", "Make sure all pods have matching ephemeral-storage requests and limits", containerStorageEphemeralRequestEqualsLimit)
@@ -36,21 +40,22 @@ func containerResources(requireCPULimit bool, requireMemoryLimit bool) func(ks.P
 		hasMissingLimit := false
 		hasMissingRequest := false
 
-		for _, container := range allContainers {
+		for i, container := range allContainers {
+			resources := internal.ContainerFieldPath(pod, i) + ".resources"
 			if container.Resources.Limits.Cpu().IsZero() && requireCPULimit {
-				score.AddComment(container.Name, "CPU limit is not set", "Resource limits are recommended to avoid resource DDOS. Set resources.limits.cpu")
+				score.AddCommentWithFieldPath(container.Name, resources+".limits", "CPU limit is not set", "Resource limits are recommended to avoid resource DDOS. Set resources.limits.cpu")
 				hasMissingLimit = true
 			}
 			if container.Resources.Limits.Memory().IsZero() && requireMemoryLimit {
-				score.AddComment(container.Name, "Memory limit is not set", "Resource limits are recommended to avoid resource DDOS. Set resources.limits.memory")
+				score.AddCommentWithFieldPath(container.Name, resources+".limits", "Memory limit is not set", "Resource limits are recommended to avoid resource DDOS. Set resources.limits.memory")
 				hasMissingLimit = true
 			}
 			if container.Resources.Requests.Cpu().IsZero() {
-				score.AddComment(container.Name, "CPU request is not set", "Resource requests are recommended to make sure that the application can start and run without crashing. Set resources.requests.cpu")
+				score.AddCommentWithFieldPath(container.Name, resources+".requests", "CPU request is not set", "Resource requests are recommended to make sure that the application can start and run without crashing. Set resources.requests.cpu")
 				hasMissingRequest = true
 			}
 			if container.Resources.Requests.Memory().IsZero() {
-				score.AddComment(container.Name, "Memory request is not set", "Resource requests are recommended to make sure that the application can start and run without crashing. Set resources.requests.memory")
+				score.AddCommentWithFieldPath(container.Name, resources+".requests", "Memory request is not set", "Resource requests are recommended to make sure that the application can start and run without crashing. Set resources.requests.memory")
 				hasMissingRequest = true
 			}
 		}
@@ -98,11 +103,11 @@ func containerCPURequestsEqualLimits(ps ks.PodSpecer) (score scorecard.TestScore
 
 	resourcesDoNotMatch := false
 
-	for _, container := range allContainers {
+	for i, container := range allContainers {
 		requests := &container.Resources.Requests
 		limits := &container.Resources.Limits
 		if !requests.Cpu().Equal(*limits.Cpu()) {
-			score.AddComment(container.Name, "CPU requests does not match limits", "Having equal requests and limits is recommended to avoid resource DDOS of the node during spikes. Set resources.requests.cpu == resources.limits.cpu")
+			score.AddCommentWithFieldPath(container.Name, internal.ContainerFieldPath(pod, i)+".resources", "CPU requests does not match limits", "Having equal requests and limits is recommended to avoid resource DDOS of the node during spikes. Set resources.requests.cpu == resources.limits.cpu")
 			resourcesDoNotMatch = true
 		}
 	}
@@ -125,11 +130,11 @@ func containerMemoryRequestsEqualLimits(ps ks.PodSpecer) (score scorecard.TestSc
 
 	resourcesDoNotMatch := false
 
-	for _, container := range allContainers {
+	for i, container := range allContainers {
 		requests := &container.Resources.Requests
 		limits := &container.Resources.Limits
 		if !requests.Memory().Equal(*limits.Memory()) {
-			score.AddComment(container.Name, "Memory requests does not match limits", "Having equal requests and limits is recommended to avoid resource DDOS of the node during spikes. Set resources.requests.memory == resources.limits.memory")
+			score.AddCommentWithFieldPath(container.Name, internal.ContainerFieldPath(pod, i)+".resources", "Memory requests does not match limits", "Having equal requests and limits is recommended to avoid resource DDOS of the node during spikes. Set resources.requests.memory == resources.limits.memory")
 			resourcesDoNotMatch = true
 		}
 	}
@@ -143,7 +148,8 @@ func containerMemoryRequestsEqualLimits(ps ks.PodSpecer) (score scorecard.TestSc
 	return
 }
 
-// containerImageTag checks that no container is using the ":latest" tag
+// containerImageTag checks that no container is using the ":latest" tag.
+// Images that are pinned by digest are allowed to not have a tag, and invalid images are reported by containerImagePolicy.
 func containerImageTag(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
 	pod := ps.GetPodTemplateSpec().Spec
 
@@ -152,10 +158,10 @@ func containerImageTag(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
 
 	hasTagLatest := false
 
-	for _, container := range allContainers {
-		tag := containerTag(container.Image)
-		if tag == "" || tag == "latest" {
-			score.AddComment(container.Name, "Image with latest tag", "Using a fixed tag is recommended to avoid accidental upgrades")
+	for i, container := range allContainers {
+		ref, err := parseImageReference(container.Image)
+		if err == nil && ref.latest() {
+			score.AddCommentWithFieldPath(container.Name, internal.ContainerFieldPath(pod, i)+".image", "Image with latest tag", "Using a fixed tag is recommended to avoid accidental upgrades")
 			hasTagLatest = true
 		}
 	}
@@ -179,18 +185,18 @@ func containerImagePullPolicy(ps ks.PodSpecer) (score scorecard.TestScore, err e
 	// Default to AllOK
 	score.Grade = scorecard.GradeAllOK
 
-	for _, container := range allContainers {
-		tag := containerTag(container.Image)
+	for i, container := range allContainers {
+		ref, err := parseImageReference(container.Image)
 
-		// If the pull policy is not set, and the tag is either empty or latest
+		// If the pull policy is not set, and the tag is either latest or empty without a digest,
 		// kubernetes will default to always pull the image
-		if container.ImagePullPolicy == corev1.PullPolicy("") && (tag == "" || tag == "latest") {
+		if container.ImagePullPolicy == corev1.PullPolicy("") && err == nil && ref.latest() {
 			continue
 		}
 
 		// No defined pull policy
 		if container.ImagePullPolicy != corev1.PullAlways || container.ImagePullPolicy == corev1.PullPolicy("") {
-			score.AddComment(container.Name, "ImagePullPolicy is not set to Always", "It's recommended to always set the ImagePullPolicy to Always, to make sure that the imagePullSecrets are always correct, and to always get the image you want.")
+			score.AddCommentWithFieldPath(container.Name, internal.ContainerFieldPath(pod, i)+".imagePullPolicy", "ImagePullPolicy is not set to Always", "It's recommended to always set the ImagePullPolicy to Always, to make sure that the imagePullSecrets are always correct, and to always get the image you want.")
 			score.Grade = scorecard.GradeCritical
 		}
 	}
@@ -198,30 +204,21 @@ func containerImagePullPolicy(ps ks.PodSpecer) (score scorecard.TestScore, err e
 	return
 }
 
-// containerTag returns the image tag
-// An empty string is returned if the image has no tag
-func containerTag(image string) string {
-	imageParts := strings.Split(image, ":")
-	if len(imageParts) > 1 {
-		imageVersion := imageParts[len(imageParts)-1]
-		return imageVersion
-	}
-	return ""
-}
-
 func containerStorageEphemeralRequestAndLimit(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
-	allContainers := ps.GetPodTemplateSpec().Spec.InitContainers
-	allContainers = append(allContainers, ps.GetPodTemplateSpec().Spec.Containers...)
+	pod := ps.GetPodTemplateSpec().Spec
+	allContainers := pod.InitContainers
+	allContainers = append(allContainers, pod.Containers...)
 
 	score.Grade = scorecard.GradeAllOK
 
-	for _, container := range allContainers {
+	for i, container := range allContainers {
+		resources := internal.ContainerFieldPath(pod, i) + ".resources"
 		if container.Resources.Limits.StorageEphemeral().IsZero() {
-			score.AddComment(container.Name, "Ephemeral Storage limit is not set",
+			score.AddCommentWithFieldPath(container.Name, resources+".limits", "Ephemeral Storage limit is not set",
 				"Resource limits are recommended to avoid resource DDOS. Set resources.limits.ephemeral-storage")
 			score.Grade = scorecard.GradeCritical
 		} else if container.Resources.Requests.StorageEphemeral().IsZero() {
-			score.AddComment(container.Name, "Ephemeral Storage request is not set",
+			score.AddCommentWithFieldPath(container.Name, resources+".requests", "Ephemeral Storage request is not set",
 				"Resource requests are recommended to make sure the application can start and run without crashing. Set resource.requests.ephemeral-storage")
 			score.Grade = scorecard.GradeWarning
 		}
@@ -231,17 +228,18 @@ func containerStorageEphemeralRequestAndLimit(ps ks.PodSpecer) (score scorecard.
 }
 
 func containerStorageEphemeralRequestEqualsLimit(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
-	allContainers := ps.GetPodTemplateSpec().Spec.InitContainers
-	allContainers = append(allContainers, ps.GetPodTemplateSpec().Spec.Containers...)
+	pod := ps.GetPodTemplateSpec().Spec
+	allContainers := pod.InitContainers
+	allContainers = append(allContainers, pod.Containers...)
 
 	score.Grade = scorecard.GradeAllOK
 
-	for _, container := range allContainers {
+	for i, container := range allContainers {
 		if !container.Resources.Limits.StorageEphemeral().IsZero() && !container.Resources.Requests.StorageEphemeral().IsZero() {
 			requests := &container.Resources.Requests
 			limits := &container.Resources.Limits
 			if !requests.StorageEphemeral().Equal(*limits.StorageEphemeral()) {
-				score.AddComment(container.Name, "Ephemeral Storage request does not match limit", "Having equal requests and limits is recommended to avoid node resource DDOS during spikes")
+				score.AddCommentWithFieldPath(container.Name, internal.ContainerFieldPath(pod, i)+".resources", "Ephemeral Storage request does not match limit", "Having equal requests and limits is recommended to avoid node resource DDOS during spikes")
 				score.Grade = scorecard.GradeCritical
 			}
 		}
@@ -256,28 +254,30 @@ func containerStorageEphemeralRequestEqualsLimit(ps ks.PodSpecer) (score scoreca
 func containerPortsCheck(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
 	const maxPortNameLength = 15
 
-	allContainers := ps.GetPodTemplateSpec().Spec.InitContainers
-	allContainers = append(allContainers, ps.GetPodTemplateSpec().Spec.Containers...)
+	pod := ps.GetPodTemplateSpec().Spec
+	allContainers := pod.InitContainers
+	allContainers = append(allContainers, pod.Containers...)
 
 	score.Grade = scorecard.GradeAllOK
 
-	for _, container := range allContainers {
+	for i, container := range allContainers {
 		names := make(map[string]bool)
-		for _, port := range container.Ports {
+		for j, port := range container.Ports {
+			portPath := fmt.Sprintf("%s.ports[%d]", internal.ContainerFieldPath(pod, i), j)
 			if len(port.Name) > 0 {
 				if _, ok := names[port.Name]; !ok {
 					names[port.Name] = true
 				} else {
-					score.AddComment(container.Name, "Container Port Check", "Container ports.containerPort named ports must be unique")
+					score.AddCommentWithFieldPath(container.Name, portPath+".name", "Container Port Check", "Container ports.containerPort named ports must be unique")
 					score.Grade = scorecard.GradeCritical
 				}
 			}
 			if len(port.Name) > maxPortNameLength {
-				score.AddComment(container.Name, "Container Port Check", "Container port.Name length exceeds maximum permitted characters")
+				score.AddCommentWithFieldPath(container.Name, portPath+".name", "Container Port Check", "Container port.Name length exceeds maximum permitted characters")
 				score.Grade = scorecard.GradeCritical
 			}
 			if port.ContainerPort == 0 {
-				score.AddComment(container.Name, "Container Port Check", "Container ports.containerPort cannot be empty")
+				score.AddCommentWithFieldPath(container.Name, portPath+".containerPort", "Container Port Check", "Container ports.containerPort cannot be empty")
 				score.Grade = scorecard.GradeCritical
 			}
 		}
@@ -295,12 +295,12 @@ func environmentVariableKeyDuplication(ps ks.PodSpecer) (score scorecard.TestSco
 
 	score.Grade = scorecard.GradeAllOK
 
-	for _, container := range allContainers {
+	for i, container := range allContainers {
 		envs := make(map[string]struct{})
-		for _, env := range container.Env {
+		for j, env := range container.Env {
 			if _, duplicated := envs[env.Name]; duplicated {
 				msg := fmt.Sprintf("Container environment variable key '%s' is duplicated", env.Name)
-				score.AddComment(container.Name, "Environment Variable Key Duplication", msg)
+				score.AddCommentWithFieldPath(container.Name, fmt.Sprintf("%s.env[%d]", internal.ContainerFieldPath(pod, i), j), "Environment Variable Key Duplication", msg)
 				score.Grade = scorecard.GradeCritical
 				continue
 			}
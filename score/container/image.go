@@ -0,0 +1,160 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/score/internal"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+// defaultRegistry is the registry of images that do not include a registry, such as "nginx"
+const defaultRegistry = "docker.io"
+
+var (
+	// pathComponent is a component of the repository, which is separated from the other components by "/"
+	pathComponent = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*$`)
+	registryRe    = regexp.MustCompile(`^(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*|\[[a-fA-F0-9:]+\])(?::[0-9]+)?$`)
+	tagRe         = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	digestRe      = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-zA-Z0-9=_-]{32,}$`)
+	sha256Re      = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+)
+
+// imageReference is a parsed container image, such as "registry.example.com:5000/team/app:v1.2@sha256:..."
+type imageReference struct {
+	// Registry is the host, and optional port, of the registry. Images without a registry are in defaultRegistry.
+	Registry string
+
+	// Repository is the path of the image in the registry. Official images on Docker Hub are in the "library" namespace.
+	Repository string
+
+	// Tag is empty if the image has no tag
+	Tag string
+
+	// Digest is empty if the image is not pinned by digest
+	Digest string
+}
+
+// Name returns the registry and the repository of the image
+func (r imageReference) Name() string {
+	return r.Registry + "/" + r.Repository
+}
+
+// latest returns true if the image is pulled by the latest tag, either explicitly, or because it has no tag and no digest
+func (r imageReference) latest() bool {
+	return r.Tag == "latest" || (r.Tag == "" && r.Digest == "")
+}
+
+// parseImageReference parses an image reference, with the same rules as the container runtimes.
+// The first component is the registry if it contains a "." or a ":", or if it is "localhost".
+func parseImageReference(image string) (imageReference, error) {
+	var ref imageReference
+	if image == "" {
+		return ref, errors.New("the image is empty")
+	}
+
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ref.Digest = name[:i], name[i+1:]
+		if !digestRe.MatchString(ref.Digest) {
+			return ref, fmt.Errorf("the digest %q is invalid", ref.Digest)
+		}
+	}
+
+	// The tag is after the last ":", unless that ":" separates the registry from its port
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+		if !tagRe.MatchString(ref.Tag) {
+			return ref, fmt.Errorf("the tag %q is invalid", ref.Tag)
+		}
+	}
+
+	ref.Registry = defaultRegistry
+	if i := strings.Index(name, "/"); i >= 0 {
+		first := name[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			if !registryRe.MatchString(first) {
+				return ref, fmt.Errorf("the registry %q is invalid", first)
+			}
+			ref.Registry, name = first, name[i+1:]
+		}
+	}
+	if ref.Registry == defaultRegistry && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+
+	for _, component := range strings.Split(name, "/") {
+		if !pathComponent.MatchString(component) {
+			return ref, fmt.Errorf("the repository %q is invalid", name)
+		}
+	}
+	ref.Repository = name
+
+	return ref, nil
+}
+
+// registryAllowed returns true if the image is in one of the allowed registries, or repository prefixes.
+// A prefix only matches whole components, so that "registry.example.com/team" does not allow "registry.example.com/teams".
+func registryAllowed(ref imageReference, allowed []string) bool {
+	name := ref.Name()
+	for _, prefix := range allowed {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if name == prefix || strings.HasPrefix(name, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// containerImagePolicy checks that all images are allowed by the image policy of the configuration
+func containerImagePolicy(cnf config.Configuration) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		pod := ps.GetPodTemplateSpec().Spec
+		allContainers := pod.InitContainers
+		allContainers = append(allContainers, pod.Containers...)
+
+		score.Grade = scorecard.GradeAllOK
+		setGrade := func(grade scorecard.Grade) {
+			if grade < score.Grade {
+				score.Grade = grade
+			}
+		}
+
+		for i, container := range allContainers {
+			fieldPath := internal.ContainerFieldPath(pod, i) + ".image"
+
+			ref, err := parseImageReference(container.Image)
+			if err != nil {
+				setGrade(scorecard.GradeCritical)
+				score.AddCommentWithFieldPath(container.Name, fieldPath, "The image reference is invalid", fmt.Sprintf("The image %q can not be pulled: %s.", container.Image, err))
+				continue
+			}
+
+			if len(cnf.AllowedImageRegistries) > 0 && !registryAllowed(ref, cnf.AllowedImageRegistries) {
+				setGrade(scorecard.GradeCritical)
+				score.AddCommentWithFieldPath(container.Name, fieldPath, fmt.Sprintf("The image is pulled from %s, which is not allowed", ref.Registry), fmt.Sprintf("Images must be pulled from one of: %s. Mirror %s to an allowed registry.", strings.Join(cnf.AllowedImageRegistries, ", "), ref.Name()))
+			}
+
+			if cnf.RequireImageDigest && !sha256Re.MatchString(ref.Digest) {
+				setGrade(scorecard.GradeCritical)
+				score.AddCommentWithFieldPath(container.Name, fieldPath, "The image is not pinned by digest", "Tags can be moved to other images. Pin the image with @sha256:<digest> to make sure that the same image is always used.")
+			}
+
+			if ref.Tag != "" {
+				for _, pattern := range cnf.ForbiddenImageTags {
+					if pattern.MatchString(ref.Tag) {
+						setGrade(scorecard.GradeCritical)
+						score.AddCommentWithFieldPath(container.Name, fieldPath, fmt.Sprintf("The image tag %s is not allowed", ref.Tag), fmt.Sprintf("The tag matches the forbidden pattern %s. Use a release tag instead.", strings.TrimSuffix(strings.TrimPrefix(pattern.String(), "^(?:"), ")$")))
+						break
+					}
+				}
+			}
+		}
+
+		return
+	}
+}
@@ -0,0 +1,51 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseImageReference(t *testing.T) {
+	t.Parallel()
+	digest := "sha256:4b8c4b8c4b8c4b8c4b8c4b8c4b8c4b8c4b8c4b8c4b8c4b8c4b8c4b8c4b8c4b8c"
+
+	tests := []struct {
+		image    string
+		expected imageReference
+	}{
+		{"nginx", imageReference{Registry: "docker.io", Repository: "library/nginx"}},
+		{"nginx:1.25", imageReference{Registry: "docker.io", Repository: "library/nginx", Tag: "1.25"}},
+		{"foo/bar:123", imageReference{Registry: "docker.io", Repository: "foo/bar", Tag: "123"}},
+		{"registry:5000/app", imageReference{Registry: "registry:5000", Repository: "app"}},
+		{"registry:5000/app:1.0", imageReference{Registry: "registry:5000", Repository: "app", Tag: "1.0"}},
+		{"localhost/app:dev", imageReference{Registry: "localhost", Repository: "app", Tag: "dev"}},
+		{"ghcr.io/org/team/app@" + digest, imageReference{Registry: "ghcr.io", Repository: "org/team/app", Digest: digest}},
+		{"gcr.io/app:v1@" + digest, imageReference{Registry: "gcr.io", Repository: "app", Tag: "v1", Digest: digest}},
+	}
+
+	for _, tc := range tests {
+		ref, err := parseImageReference(tc.image)
+		assert.NoError(t, err, tc.image)
+		assert.Equal(t, tc.expected, ref, tc.image)
+	}
+}
+
+func TestParseImageReferenceInvalid(t *testing.T) {
+	t.Parallel()
+	for _, image := range []string{"", "Foo/Bar", "app:", "app:-1", "app@sha256", "app@sha256:abc", "registry..com/app", "/app"} {
+		_, err := parseImageReference(image)
+		assert.Error(t, err, image)
+	}
+}
+
+func TestRegistryAllowed(t *testing.T) {
+	t.Parallel()
+	ref, err := parseImageReference("registry.example.com/team/app:1.0")
+	assert.NoError(t, err)
+
+	assert.True(t, registryAllowed(ref, []string{"registry.example.com"}))
+	assert.True(t, registryAllowed(ref, []string{"docker.io", "registry.example.com/team/"}))
+	assert.False(t, registryAllowed(ref, []string{"registry.example.com/te"}))
+	assert.False(t, registryAllowed(ref, []string{"registry.example.com:5000"}))
+}
@@ -0,0 +1,203 @@
+package container
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/score/internal"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+const (
+	// minPlausibleMemory is the smallest memory quantity that is not reported as suspicious.
+	// Smaller quantities are usually missing a unit, such as "512" instead of "512Mi".
+	minPlausibleMemory = 1024 * 1024
+
+	// maxPlausibleCPU is the largest number of cores that is not reported as suspicious.
+	// Larger quantities are usually missing a unit, such as "500" instead of "500m".
+	maxPlausibleCPU = 64
+)
+
+// resourceLists returns the requests and limits of the container, keyed by their field name
+func resourceLists(container corev1.Container) []struct {
+	name string
+	list corev1.ResourceList
+} {
+	return []struct {
+		name string
+		list corev1.ResourceList
+	}{
+		{"requests", container.Resources.Requests},
+		{"limits", container.Resources.Limits},
+	}
+}
+
+// sortedResourceNames returns the names of the resources in the list, in a deterministic order
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// containerResourceLimitsAboveRequests checks that no limit is lower than the request of the same resource
+func containerResourceLimitsAboveRequests(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+	pod := ps.GetPodTemplateSpec().Spec
+	allContainers := pod.InitContainers
+	allContainers = append(allContainers, pod.Containers...)
+
+	score.Grade = scorecard.GradeAllOK
+
+	for i, container := range allContainers {
+		for _, name := range sortedResourceNames(container.Resources.Limits) {
+			limit := container.Resources.Limits[name]
+			request, ok := container.Resources.Requests[name]
+			if !ok || limit.Cmp(request) >= 0 {
+				continue
+			}
+			score.Grade = scorecard.GradeCritical
+			score.AddCommentWithFieldPath(container.Name, fmt.Sprintf("%s.resources.limits.%s", internal.ContainerFieldPath(pod, i), name),
+				fmt.Sprintf("The %s limit %s is lower than the request %s", name, limit.String(), request.String()),
+				"Limits can not be lower than requests, and the pod is rejected by the API server. Raise the limit, or lower the request.")
+		}
+	}
+
+	return
+}
+
+// containerResourceUnits checks that CPU and memory quantities have plausible units
+func containerResourceUnits(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+	pod := ps.GetPodTemplateSpec().Spec
+	allContainers := pod.InitContainers
+	allContainers = append(allContainers, pod.Containers...)
+
+	score.Grade = scorecard.GradeAllOK
+
+	for i, container := range allContainers {
+		resources := internal.ContainerFieldPath(pod, i) + ".resources"
+		for _, l := range resourceLists(container) {
+			if cpu, ok := l.list[corev1.ResourceCPU]; ok && cpu.Cmp(*resource.NewQuantity(maxPlausibleCPU, resource.DecimalSI)) > 0 {
+				score.Grade = scorecard.GradeWarning
+				score.AddCommentWithFieldPath(container.Name, fmt.Sprintf("%s.%s.cpu", resources, l.name),
+					fmt.Sprintf("The CPU %s %s is %s cores", l.name, cpu.String(), cpu.AsDec().String()),
+					fmt.Sprintf("CPU quantities without a unit are in cores. Use the m suffix for millicores, such as %dm.", cpu.Value()))
+			}
+
+			memory, ok := l.list[corev1.ResourceMemory]
+			if !ok {
+				continue
+			}
+			fieldPath := fmt.Sprintf("%s.%s.memory", resources, l.name)
+			formatted := memory.String()
+			unit := formatted[len(formatted)-1:]
+			switch {
+			case memory.MilliValue()%1000 != 0:
+				score.Grade = scorecard.GradeWarning
+				score.AddCommentWithFieldPath(container.Name, fieldPath,
+					fmt.Sprintf("The memory %s %s is a fraction of a byte", l.name, memory.String()),
+					"The m suffix is milli, and not mega. Use Mi for mebibytes, such as 512Mi.")
+			case memory.Value() < minPlausibleMemory:
+				score.Grade = scorecard.GradeWarning
+				score.AddCommentWithFieldPath(container.Name, fieldPath,
+					fmt.Sprintf("The memory %s %s is %d bytes", l.name, memory.String(), memory.Value()),
+					"Memory quantities without a unit are in bytes. Use a binary unit, such as Mi or Gi.")
+			case memory.Format == resource.DecimalSI && strings.Contains("kMGTPE", unit):
+				score.Grade = scorecard.GradeWarning
+				score.AddCommentWithFieldPath(container.Name, fieldPath,
+					fmt.Sprintf("The memory %s %s uses the decimal unit %s", l.name, formatted, unit),
+					fmt.Sprintf("Decimal units are powers of 1000, and are smaller than the binary units that memory is usually measured in. Use %si if that was intended.", strings.ToUpper(unit)))
+			}
+		}
+	}
+
+	return
+}
+
+// containerResourceBounds checks that the requests of all containers, and the ratio between their limits and requests,
+// are within the bounds of the first rule of the configuration that matches the pod
+func containerResourceBounds(rules []config.ResourceBoundsRule) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		pod := ps.GetPodTemplateSpec().Spec
+		meta := ps.GetObjectMeta()
+
+		labels := internal.PodLabels(ps)
+
+		score.Grade = scorecard.GradeAllOK
+
+		var rule *config.ResourceBoundsRule
+		for i := range rules {
+			if rules[i].Matches(meta.Namespace, labels) {
+				rule = &rules[i]
+				break
+			}
+		}
+		if rule == nil {
+			return
+		}
+
+		setGrade := func(grade scorecard.Grade) {
+			if grade < score.Grade {
+				score.Grade = grade
+			}
+		}
+
+		allContainers := pod.InitContainers
+		allContainers = append(allContainers, pod.Containers...)
+
+		for i, container := range allContainers {
+			resources := internal.ContainerFieldPath(pod, i) + ".resources"
+
+			for _, bounds := range []struct {
+				resource corev1.ResourceName
+				name     string
+				r        config.QuantityRange
+			}{
+				{corev1.ResourceCPU, "CPU", rule.CPURequest},
+				{corev1.ResourceMemory, "memory", rule.MemoryRequest},
+			} {
+				request, ok := container.Resources.Requests[bounds.resource]
+				if !ok {
+					continue
+				}
+				min, max, err := bounds.r.Quantities()
+				if err != nil {
+					return score, err
+				}
+				fieldPath := fmt.Sprintf("%s.requests.%s", resources, bounds.resource)
+				if min != nil && request.Cmp(*min) < 0 {
+					setGrade(scorecard.GradeCritical)
+					score.AddCommentWithFieldPath(container.Name, fieldPath,
+						fmt.Sprintf("The %s request %s is lower than the minimum %s", bounds.name, request.String(), min.String()),
+						"Requests that are too low can make the container starve, or be evicted. Raise the request.")
+				}
+				if max != nil && request.Cmp(*max) > 0 {
+					setGrade(scorecard.GradeCritical)
+					score.AddCommentWithFieldPath(container.Name, fieldPath,
+						fmt.Sprintf("The %s request %s is higher than the maximum %s", bounds.name, request.String(), max.String()),
+						"Requests that are too high reserve capacity that is not used, and can make the pod unschedulable. Lower the request.")
+				}
+
+				limit, ok := container.Resources.Limits[bounds.resource]
+				if !ok || rule.MaxLimitRequestRatio == 0 || request.IsZero() {
+					continue
+				}
+				if ratio := limit.AsApproximateFloat64() / request.AsApproximateFloat64(); ratio > rule.MaxLimitRequestRatio {
+					setGrade(scorecard.GradeWarning)
+					score.AddCommentWithFieldPath(container.Name, fmt.Sprintf("%s.limits.%s", resources, bounds.resource),
+						fmt.Sprintf("The %s limit is %.1f times the request", bounds.name, ratio),
+						fmt.Sprintf("The limit may be at most %v times the request. Nodes are overcommitted when many containers use more than they request. Raise the request, or lower the limit.", rule.MaxLimitRequestRatio))
+				}
+			}
+		}
+
+		return
+	}
+}
@@ -15,6 +15,7 @@ import (
 func Register(allChecks *checks.Checks, budgets ks.PodDisruptionBudgets) {
 	allChecks.RegisterStatefulSetCheck("StatefulSet has PodDisruptionBudget", `Makes sure that all StatefulSets are targeted by a PDB`, statefulSetHas(budgets.PodDisruptionBudgets()))
 	allChecks.RegisterDeploymentCheck("Deployment has PodDisruptionBudget", `Makes sure that all Deployments are targeted by a PDB`, deploymentHas(budgets.PodDisruptionBudgets()))
+	allChecks.RegisterWorkloadCheck("Workload has PodDisruptionBudget", `Makes sure that all ReplicaSets, ReplicationControllers and custom workloads are targeted by a PDB`, workloadHas(budgets.PodDisruptionBudgets()))
 	allChecks.RegisterPodDisruptionBudgetCheck("PodDisruptionBudget has policy", `Makes sure that PodDisruptionBudgets specify minAvailable or maxUnavailable`, hasPolicy)
 }
 
@@ -87,6 +88,32 @@ func deploymentHas(budgets []ks.PodDisruptionBudget) func(appsv1.Deployment) (sc
 	}
 }
 
+func workloadHas(budgets []ks.PodDisruptionBudget) func(ks.Workload) (scorecard.TestScore, error) {
+	return func(workload ks.Workload) (score scorecard.TestScore, err error) {
+		kind := workload.GetTypeMeta().Kind
+		if replicas := workload.GetReplicas(); replicas != nil && *replicas < 2 {
+			score.Skipped = true
+			score.AddComment("", fmt.Sprintf("Skipped because the %s has less than 2 replicas", kind), "")
+			return
+		}
+
+		match, matchErr := hasMatching(budgets, workload.GetObjectMeta().Namespace, workload.GetPodTemplateSpec().Labels)
+		if matchErr != nil {
+			err = matchErr
+			return
+		}
+
+		if match {
+			score.Grade = scorecard.GradeAllOK
+		} else {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment("", "No matching PodDisruptionBudget was found", "It's recommended to define a PodDisruptionBudget to avoid unexpected downtime during Kubernetes maintenance operations, such as when draining a node.")
+		}
+
+		return
+	}
+}
+
 func hasPolicy(pdb ks.PodDisruptionBudget) (score scorecard.TestScore, err error) {
 	spec := pdb.Spec()
 	if spec.MinAvailable == nil && spec.MaxUnavailable == nil {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/younes-bami/kube-score/config"
 	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/scorecard"
 )
 
 func TestFileLocationHelm(t *testing.T) {
@@ -34,3 +35,42 @@ func TestFileLocation(t *testing.T) {
 	assert.Equal(t, 2, sc["Deployment/apps/v1//foo"].FileLocation.Line)
 	assert.Equal(t, 12, sc["Deployment/apps/v1//foo2"].FileLocation.Line)
 }
+
+func TestFieldLocation(t *testing.T) {
+	sc, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("deployment-test-resources.yaml"), testFile("cronjob-batchv1.yaml")},
+		KubernetesVersion: config.Semver{Major: 1, Minor: 18},
+	})
+	assert.Nil(t, err)
+
+	comment := func(object, check string) scorecard.TestScoreComment {
+		for _, c := range sc[object].Checks {
+			if c.Check.ID == check && len(c.Comments) > 0 {
+				return c.Comments[0]
+			}
+		}
+		t.Fatalf("no comments for %s on %s", check, object)
+		return scorecard.TestScoreComment{}
+	}
+
+	// The request is missing, the location of the parent is used
+	resources := comment("Deployment/apps/v1//deployment-test-1", "container-resources")
+	assert.Equal(t, "spec.template.spec.containers[0].resources.requests", resources.FieldPath)
+	assert.Equal(t, ks.FileLocation{Name: "testdata/deployment-test-resources.yaml", Line: 11, Column: 9}, resources.FileLocation)
+
+	image := comment("CronJob/batch/v1//cronjob-test", "container-image-tag")
+	assert.Equal(t, "spec.jobTemplate.spec.template.spec.containers[0].image", image.FieldPath)
+	assert.Equal(t, ks.FileLocation{Name: "testdata/cronjob-batchv1.yaml", Line: 13, Column: 13}, image.FileLocation)
+}
+
+func TestFileLocationDocumentMarkers(t *testing.T) {
+	sc, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("linenumbers-markers.yaml")},
+		KubernetesVersion: config.Semver{Major: 1, Minor: 18},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, sc, 2)
+	assert.Equal(t, 2, sc["Deployment/apps/v1//foo"].FileLocation.Line)
+	assert.Equal(t, 18, sc["Deployment/apps/v1//foo2"].FileLocation.Line)
+	assert.Equal(t, "set -e\n---\necho done\n", sc["Deployment/apps/v1//foo"].ObjectMeta.Annotations["script"])
+}
@@ -0,0 +1,456 @@
+package gateway
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/score/checks"
+	"github.com/younes-bami/kube-score/score/internal"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+func Register(allChecks *checks.Checks, services ks.Services, gateways ks.Gateways, routes ks.Routes, metas ks.Metas) {
+	allChecks.RegisterRouteCheck("Route targets Service", `Makes sure that all backendRefs of HTTPRoutes and GRPCRoutes refer to a Service and port`, routeTargetsService(services.Services()))
+	allChecks.RegisterRouteCheck("Route has Gateway", `Makes sure that all parentRefs of HTTPRoutes and GRPCRoutes refer to a Gateway, and to a listener that allows the kind and namespace of the route and accepts its hostnames`, routeHasGateway(gateways.Gateways(), namespaces(metas.Metas())))
+	allChecks.RegisterRouteCheck("Route hostname conflicts", `Makes sure that routes that are attached to the same Gateway do not have overlapping hostnames and the same matches. Wildcard hostnames, and routes without hostnames, overlap with the hostnames that they match.`, routeHostnameConflicts(routes.Routes()))
+}
+
+func routeTargetsService(allServices []ks.Service) func(ks.Route) (scorecard.TestScore, error) {
+	return func(route ks.Route) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+		namespace := route.GetObjectMeta().Namespace
+
+		for i, rule := range route.RouteSpec().Rules {
+			for j, ref := range rule.BackendRefs {
+				// Only Services can be resolved, other kinds of backends are left to the implementation
+				if valueOr(ref.Group, "") != "" || valueOr(ref.Kind, "Service") != "Service" {
+					continue
+				}
+
+				fieldPath := fmt.Sprintf("spec.rules[%d].backendRefs[%d]", i, j)
+				refNamespace := valueOr(ref.Namespace, namespace)
+
+				if ref.Port == nil {
+					score.Grade = scorecard.GradeCritical
+					score.AddCommentWithFieldPath(ref.Name, fieldPath, "The backendRef has no port", "The port is required when the backend is a Service")
+					continue
+				}
+
+				if !hasServicePort(allServices, refNamespace, ref.Name, *ref.Port) {
+					score.Grade = scorecard.GradeCritical
+					score.AddCommentWithFieldPath(ref.Name, fieldPath, "No service match was found", fmt.Sprintf("No service with name %s and port number %d was found", ref.Name, *ref.Port))
+				}
+			}
+		}
+
+		return
+	}
+}
+
+func hasServicePort(allServices []ks.Service, namespace, name string, port int32) bool {
+	for _, srv := range allServices {
+		service := srv.Service()
+		if service.Namespace != namespace || service.Name != name {
+			continue
+		}
+		for _, servicePort := range service.Spec.Ports {
+			if servicePort.Port == port {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func routeHasGateway(allGateways []ks.Gateway, allNamespaces []ks.BothMeta) func(ks.Route) (scorecard.TestScore, error) {
+	return func(route ks.Route) (score scorecard.TestScore, err error) {
+		spec := route.RouteSpec()
+		namespace := route.GetObjectMeta().Namespace
+
+		if len(spec.ParentRefs) == 0 {
+			score.Grade = scorecard.GradeWarning
+			score.AddCommentWithFieldPath("", "spec", "The route has no parentRefs", "Routes must be attached to a Gateway with parentRefs to receive traffic")
+			return
+		}
+
+		score.Grade = scorecard.GradeAllOK
+
+		for i, ref := range spec.ParentRefs {
+			// Routes can be attached to other kinds of parents, such as Services in a service mesh
+			if !isGatewayRef(ref) {
+				continue
+			}
+
+			fieldPath := fmt.Sprintf("spec.parentRefs[%d]", i)
+			refNamespace := valueOr(ref.Namespace, namespace)
+
+			gateway, found := findGateway(allGateways, refNamespace, ref.Name)
+			if !found {
+				score.Grade = scorecard.GradeCritical
+				score.AddCommentWithFieldPath(ref.Name, fieldPath, "No Gateway match was found", fmt.Sprintf("No Gateway with name %s was found in the namespace %s", ref.Name, refNamespace))
+				continue
+			}
+
+			listeners := matchingListeners(gateway.GatewaySpec().Listeners, ref)
+			if len(listeners) == 0 {
+				score.Grade = scorecard.GradeCritical
+				score.AddCommentWithFieldPath(ref.Name, fieldPath, "No listener match was found", fmt.Sprintf("The Gateway %s has no listener with the sectionName and port of the parentRef", ref.Name))
+				continue
+			}
+
+			listeners = allowingListeners(listeners, gateway, route, allNamespaces)
+			if len(listeners) == 0 {
+				score.Grade = scorecard.GradeCritical
+				score.AddCommentWithFieldPath(ref.Name, fieldPath, "The route is not allowed by the Gateway", fmt.Sprintf("The allowedRoutes of the listeners of the Gateway %s do not allow the kind %s from the namespace %s, and the route will not be accepted", ref.Name, route.GetTypeMeta().Kind, internal.Namespace(namespace)))
+				continue
+			}
+
+			if !anyListenerAcceptsHostnames(listeners, spec.Hostnames) {
+				score.Grade = scorecard.GradeCritical
+				score.AddCommentWithFieldPath(ref.Name, "spec.hostnames", "The hostnames do not match the Gateway", fmt.Sprintf("None of the hostnames of the route match the hostnames of the listeners of the Gateway %s, and the route will not be accepted", ref.Name))
+			}
+		}
+
+		return
+	}
+}
+
+func isGatewayRef(ref ks.ParentReference) bool {
+	return valueOr(ref.Group, ks.GatewayGroup) == ks.GatewayGroup && valueOr(ref.Kind, "Gateway") == "Gateway"
+}
+
+func findGateway(allGateways []ks.Gateway, namespace, name string) (ks.Gateway, bool) {
+	for _, gateway := range allGateways {
+		if gateway.GetObjectMeta().Namespace == namespace && gateway.GetObjectMeta().Name == name {
+			return gateway, true
+		}
+	}
+	return nil, false
+}
+
+// matchingListeners returns the listeners that a parentRef selects with its sectionName and port
+func matchingListeners(listeners []ks.Listener, ref ks.ParentReference) []ks.Listener {
+	var res []ks.Listener
+	for _, listener := range listeners {
+		if ref.SectionName != nil && *ref.SectionName != listener.Name {
+			continue
+		}
+		if ref.Port != nil && *ref.Port != listener.Port {
+			continue
+		}
+		res = append(res, listener)
+	}
+	return res
+}
+
+// allowingListeners returns the listeners that allow the route to be attached with their allowedRoutes
+func allowingListeners(listeners []ks.Listener, gateway ks.Gateway, route ks.Route, allNamespaces []ks.BothMeta) []ks.Listener {
+	var res []ks.Listener
+	for _, listener := range listeners {
+		allowed := ks.AllowedRoutes{}
+		if listener.AllowedRoutes != nil {
+			allowed = *listener.AllowedRoutes
+		}
+		if len(allowed.Kinds) > 0 && !allowsKind(allowed.Kinds, route.GetTypeMeta()) {
+			continue
+		}
+		if !allowsNamespace(allowed.Namespaces, gateway.GetObjectMeta().Namespace, route.GetObjectMeta().Namespace, allNamespaces) {
+			continue
+		}
+		res = append(res, listener)
+	}
+	return res
+}
+
+func allowsKind(kinds []ks.RouteGroupKind, typeMeta metav1.TypeMeta) bool {
+	group, _, _ := strings.Cut(typeMeta.APIVersion, "/")
+	for _, kind := range kinds {
+		if valueOr(kind.Group, ks.GatewayGroup) == group && kind.Kind == typeMeta.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsNamespace returns true if routes in the namespace can be attached to a listener of a Gateway in gatewayNamespace.
+// Routes are only allowed from the namespace of the Gateway by default. The labels of namespaces are only known if the
+// Namespace is in the input, routes from other namespaces are assumed to be selected.
+func allowsNamespace(namespaces *ks.RouteNamespaces, gatewayNamespace, namespace string, allNamespaces []ks.BothMeta) bool {
+	from := "Same"
+	var selector *metav1.LabelSelector
+	if namespaces != nil {
+		from = valueOr(namespaces.From, "Same")
+		selector = namespaces.Selector
+	}
+
+	switch from {
+	case "All":
+		return true
+	case "Selector":
+		name := internal.Namespace(namespace)
+		var ns *ks.BothMeta
+		for i := range allNamespaces {
+			if allNamespaces[i].ObjectMeta.Name == name {
+				ns = &allNamespaces[i]
+			}
+		}
+		if ns == nil {
+			return true
+		}
+
+		sel, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return false
+		}
+		nsLabels := labels.Set{"kubernetes.io/metadata.name": name}
+		for k, v := range ns.ObjectMeta.Labels {
+			nsLabels[k] = v
+		}
+		return sel.Matches(nsLabels)
+	default:
+		return internal.Namespace(namespace) == internal.Namespace(gatewayNamespace)
+	}
+}
+
+// namespaces returns the Namespaces in the input
+func namespaces(metas []ks.BothMeta) []ks.BothMeta {
+	var res []ks.BothMeta
+	for _, meta := range metas {
+		if meta.TypeMeta.APIVersion == "v1" && meta.TypeMeta.Kind == "Namespace" {
+			res = append(res, meta)
+		}
+	}
+	return res
+}
+
+func anyListenerAcceptsHostnames(listeners []ks.Listener, hostnames []string) bool {
+	for _, listener := range listeners {
+		// Listeners and routes without hostnames accept all hostnames
+		if listener.Hostname == nil || *listener.Hostname == "" || len(hostnames) == 0 {
+			return true
+		}
+		for _, hostname := range hostnames {
+			if hostnamesIntersect(*listener.Hostname, hostname) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hostnamesIntersect returns true if there is a hostname that is matched by both a and b, which can be wildcards such as "*.example.com"
+func hostnamesIntersect(a, b string) bool {
+	if a == b {
+		return true
+	}
+	matchesWildcard := func(wildcard, hostname string) bool {
+		if !strings.HasPrefix(wildcard, "*.") {
+			return false
+		}
+		suffix := wildcard[1:]
+		return strings.HasSuffix(hostname, suffix) && len(hostname) > len(suffix)
+	}
+	return matchesWildcard(a, b) || matchesWildcard(b, a)
+}
+
+func routeHostnameConflicts(allRoutes []ks.Route) func(ks.Route) (scorecard.TestScore, error) {
+	return func(route ks.Route) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+
+		for _, other := range allRoutes {
+			if other.GetTypeMeta().Kind != route.GetTypeMeta().Kind || isSameObject(route, other) {
+				continue
+			}
+			if !shareParent(route, other) {
+				continue
+			}
+
+			for _, hostname := range commonHostnames(route.RouteSpec().Hostnames, other.RouteSpec().Hostnames) {
+				precedence := "Only the oldest of the routes will receive the traffic."
+				if !hasHostname(route, hostname) || !hasHostname(other, hostname) {
+					precedence = "Only the route with the most specific hostname will receive the traffic, which can hide the rules of the other route."
+				}
+				for _, match := range commonMatches(route, other) {
+					score.Grade = scorecard.GradeWarning
+					score.AddCommentWithFieldPath(hostname, "spec.hostnames",
+						fmt.Sprintf("The route conflicts with %s %s", other.GetTypeMeta().Kind, other.GetObjectMeta().Name),
+						fmt.Sprintf("Both routes are attached to the same Gateway, and match %s on the hostname %s. %s", match, hostname, precedence),
+					)
+				}
+			}
+		}
+
+		return
+	}
+}
+
+// hasHostname returns true if the route lists the hostname. Routes without hostnames are shown as "*".
+func hasHostname(route ks.Route, hostname string) bool {
+	hostnames := route.RouteSpec().Hostnames
+	if len(hostnames) == 0 {
+		return hostname == "*"
+	}
+	for _, h := range hostnames {
+		if h == hostname {
+			return true
+		}
+	}
+	return false
+}
+
+func isSameObject(a, b ks.Route) bool {
+	return a.GetObjectMeta().Namespace == b.GetObjectMeta().Namespace && a.GetObjectMeta().Name == b.GetObjectMeta().Name
+}
+
+// shareParent returns true if the routes are attached to the same listener of a Gateway
+func shareParent(a, b ks.Route) bool {
+	for _, refA := range a.RouteSpec().ParentRefs {
+		for _, refB := range b.RouteSpec().ParentRefs {
+			if !isGatewayRef(refA) || !isGatewayRef(refB) {
+				continue
+			}
+			if refA.Name != refB.Name || valueOr(refA.Namespace, a.GetObjectMeta().Namespace) != valueOr(refB.Namespace, b.GetObjectMeta().Namespace) {
+				continue
+			}
+			if refA.SectionName != nil && refB.SectionName != nil && *refA.SectionName != *refB.SectionName {
+				continue
+			}
+			if refA.Port != nil && refB.Port != nil && *refA.Port != *refB.Port {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// commonHostnames returns the hostnames that are matched by both lists. Wildcards such as "*.example.com" match the
+// hostnames below them, and the more specific of two intersecting hostnames is returned. Routes without hostnames match
+// all hostnames, shown as "*".
+func commonHostnames(a, b []string) []string {
+	if len(a) == 0 {
+		a = []string{"*"}
+	}
+	if len(b) == 0 {
+		b = []string{"*"}
+	}
+
+	var res []string
+	seen := make(map[string]struct{})
+	for _, hostnameA := range a {
+		for _, hostnameB := range b {
+			var hostname string
+			switch {
+			case hostnameA == "*":
+				hostname = hostnameB
+			case hostnameB == "*":
+				hostname = hostnameA
+			case hostnamesIntersect(hostnameA, hostnameB):
+				hostname = moreSpecificHostname(hostnameA, hostnameB)
+			default:
+				continue
+			}
+			if _, ok := seen[hostname]; ok {
+				continue
+			}
+			seen[hostname] = struct{}{}
+			res = append(res, hostname)
+		}
+	}
+	return res
+}
+
+// moreSpecificHostname returns the hostname that matches fewer hostnames, of two hostnames that intersect
+func moreSpecificHostname(a, b string) string {
+	if !strings.HasPrefix(a, "*.") {
+		return a
+	}
+	if !strings.HasPrefix(b, "*.") || len(b) > len(a) {
+		return b
+	}
+	return a
+}
+
+// commonMatches returns the matches that are identical in the rules of both routes
+func commonMatches(a, b ks.Route) []string {
+	matchesB := make(map[string]struct{})
+	for _, key := range matchKeys(b) {
+		matchesB[key] = struct{}{}
+	}
+
+	var res []string
+	seen := make(map[string]struct{})
+	for _, key := range matchKeys(a) {
+		if _, ok := matchesB[key]; !ok {
+			continue
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		res = append(res, key)
+	}
+	return res
+}
+
+// matchKeys returns a description of each match of the route, that is equal for equal matches
+func matchKeys(route ks.Route) []string {
+	isGRPC := route.GetTypeMeta().Kind == "GRPCRoute"
+
+	var res []string
+	for _, rule := range route.RouteSpec().Rules {
+		// A rule without matches matches all requests
+		if len(rule.Matches) == 0 {
+			rule.Matches = []ks.RouteMatch{{}}
+		}
+		for _, match := range rule.Matches {
+			var parts []string
+			if isGRPC {
+				if match.GRPCMethod != nil {
+					parts = append(parts, fmt.Sprintf("method %s %s/%s", valueOr(match.GRPCMethod.Type, "Exact"), valueOr(match.GRPCMethod.Service, "*"), valueOr(match.GRPCMethod.Method, "*")))
+				} else {
+					parts = append(parts, "all methods")
+				}
+			} else {
+				path := ks.PathMatch{}
+				if match.Path != nil {
+					path = *match.Path
+				}
+				parts = append(parts, fmt.Sprintf("path %s %s", valueOr(path.Type, "PathPrefix"), valueOr(path.Value, "/")))
+				if match.Method != nil {
+					parts = append(parts, "method "+*match.Method)
+				}
+				parts = append(parts, headerKeys("query parameter", match.QueryParams)...)
+			}
+			parts = append(parts, headerKeys("header", match.Headers)...)
+			res = append(res, strings.Join(parts, ", "))
+		}
+	}
+	return res
+}
+
+func headerKeys(kind string, headers []ks.HeaderMatch) []string {
+	var res []string
+	for _, header := range headers {
+		name := header.Name
+		// Header names are case insensitive
+		if kind == "header" {
+			name = strings.ToLower(name)
+		}
+		res = append(res, fmt.Sprintf("%s %s %s=%s", kind, valueOr(header.Type, "Exact"), name, header.Value))
+	}
+	sort.Strings(res)
+	return res
+}
+
+func valueOr[T any](v *T, def T) T {
+	if v == nil {
+		return def
+	}
+	return *v
+}
@@ -0,0 +1,134 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+// gatewayConfig returns a new configuration that scores gateway-api.yaml
+func gatewayConfig() config.Configuration {
+	return config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("gateway-api.yaml")},
+		KubernetesVersion: config.Semver{Major: 1, Minor: 27},
+	}
+}
+
+func TestRouteTargetsService(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, gatewayConfig(), "HTTPRoute/gateway.networking.k8s.io/v1/apps/app", "route-targets-service").Grade)
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, gatewayConfig(), "GRPCRoute/gateway.networking.k8s.io/v1/apps/grpc", "route-targets-service").Grade)
+
+	res := testObjectCheck(t, gatewayConfig(), "HTTPRoute/gateway.networking.k8s.io/v1/apps/conflicting", "route-targets-service")
+	assert.Equal(t, scorecard.GradeCritical, res.Grade)
+	assert.Len(t, res.Comments, 2)
+	assert.Equal(t, "No service with name app and port number 9090 was found", res.Comments[0].Description)
+	assert.Equal(t, "spec.rules[0].backendRefs[0]", res.Comments[0].FieldPath)
+	assert.Equal(t, "No service with name missing and port number 80 was found", res.Comments[1].Description)
+	assert.Equal(t, 71, res.Comments[1].FileLocation.Line)
+}
+
+func TestRouteHasGateway(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, gatewayConfig(), "HTTPRoute/gateway.networking.k8s.io/v1/apps/app", "route-has-gateway").Grade)
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, gatewayConfig(), "HTTPRoute/gateway.networking.k8s.io/v1/apps/conflicting", "route-has-gateway").Grade)
+
+	res := testObjectCheck(t, gatewayConfig(), "HTTPRoute/gateway.networking.k8s.io/v1/apps/wrong-hostname", "route-has-gateway")
+	assert.Equal(t, scorecard.GradeCritical, res.Grade)
+	var summaries []string
+	for _, c := range res.Comments {
+		summaries = append(summaries, c.Summary)
+	}
+	assert.Equal(t, []string{
+		"The hostnames do not match the Gateway",
+		"No Gateway match was found",
+		"No listener match was found",
+	}, summaries)
+}
+
+func TestRouteHasGatewayAllowedRoutes(t *testing.T) {
+	t.Parallel()
+	allowedRoutesConfig := func() config.Configuration {
+		return config.Configuration{
+			AllFiles:          []ks.NamedReader{testFile("gateway-allowed-routes.yaml")},
+			KubernetesVersion: config.Semver{Major: 1, Minor: 27},
+		}
+	}
+
+	// Routes are only allowed from the namespace of the Gateway by default
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, allowedRoutesConfig(), "HTTPRoute/gateway.networking.k8s.io/v1/infra/same-namespace", "route-has-gateway").Grade)
+	res := testObjectCheck(t, allowedRoutesConfig(), "HTTPRoute/gateway.networking.k8s.io/v1/apps/other-namespace", "route-has-gateway")
+	assert.Equal(t, scorecard.GradeCritical, res.Grade)
+	assert.Len(t, res.Comments, 1)
+	assert.Equal(t, "The route is not allowed by the Gateway", res.Comments[0].Summary)
+	assert.Equal(t, "The allowedRoutes of the listeners of the Gateway shared do not allow the kind HTTPRoute from the namespace apps, and the route will not be accepted", res.Comments[0].Description)
+	assert.Equal(t, "spec.parentRefs[0]", res.Comments[0].FieldPath)
+
+	// Kinds
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, allowedRoutesConfig(), "GRPCRoute/gateway.networking.k8s.io/v1/apps/grpc", "route-has-gateway").Grade)
+	assert.Equal(t, scorecard.GradeCritical, testObjectCheck(t, allowedRoutesConfig(), "HTTPRoute/gateway.networking.k8s.io/v1/apps/http", "route-has-gateway").Grade)
+
+	// Namespace selectors
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, allowedRoutesConfig(), "HTTPRoute/gateway.networking.k8s.io/v1/apps/selected", "route-has-gateway").Grade)
+	assert.Equal(t, scorecard.GradeCritical, testObjectCheck(t, allowedRoutesConfig(), "HTTPRoute/gateway.networking.k8s.io/v1/apps/not-selected", "route-has-gateway").Grade)
+
+	// The labels of the namespace are not known
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, allowedRoutesConfig(), "HTTPRoute/gateway.networking.k8s.io/v1/web/unknown-namespace", "route-has-gateway").Grade)
+
+	// Without a sectionName, the route is attached to the listeners that allow it
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, allowedRoutesConfig(), "HTTPRoute/gateway.networking.k8s.io/v1/apps/any-listener", "route-has-gateway").Grade)
+}
+
+func TestRouteHostnameConflicts(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, gatewayConfig(), "GRPCRoute/gateway.networking.k8s.io/v1/apps/grpc", "route-hostname-conflicts").Grade)
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, gatewayConfig(), "HTTPRoute/gateway.networking.k8s.io/v1/apps/wrong-hostname", "route-hostname-conflicts").Grade)
+
+	res := testObjectCheck(t, gatewayConfig(), "HTTPRoute/gateway.networking.k8s.io/v1/apps/app", "route-hostname-conflicts")
+	assert.Equal(t, scorecard.GradeWarning, res.Grade)
+	assert.Len(t, res.Comments, 1)
+	assert.Equal(t, "The route conflicts with HTTPRoute conflicting", res.Comments[0].Summary)
+	assert.Equal(t, "Both routes are attached to the same Gateway, and match path PathPrefix /api on the hostname app.example.com. Only the oldest of the routes will receive the traffic.", res.Comments[0].Description)
+}
+
+func TestRouteHostnameConflictsWildcard(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("gateway-hostname-overlap.yaml")},
+		KubernetesVersion: config.Semver{Major: 1, Minor: 27},
+	})
+	assert.NoError(t, err)
+
+	check := func(object string) scorecard.TestScore {
+		for _, c := range sc[object].Checks {
+			if c.Check.ID == "route-hostname-conflicts" {
+				return c
+			}
+		}
+		t.Fatalf("route-hostname-conflicts was not tested on %s", object)
+		return scorecard.TestScore{}
+	}
+
+	// *.example.com matches shop.example.com
+	res := check("HTTPRoute/gateway.networking.k8s.io/v1/apps/wildcard")
+	assert.Equal(t, scorecard.GradeWarning, res.Grade)
+	assert.Len(t, res.Comments, 1)
+	assert.Equal(t, "The route conflicts with HTTPRoute shop", res.Comments[0].Summary)
+	assert.Equal(t, "shop.example.com", res.Comments[0].Path)
+	assert.Equal(t, "Both routes are attached to the same Gateway, and match path PathPrefix /cart on the hostname shop.example.com. Only the route with the most specific hostname will receive the traffic, which can hide the rules of the other route.", res.Comments[0].Description)
+
+	// Routes without hostnames match all hostnames
+	res = check("HTTPRoute/gateway.networking.k8s.io/v1/apps/all-hostnames")
+	assert.Equal(t, scorecard.GradeWarning, res.Grade)
+	assert.Len(t, res.Comments, 1)
+	assert.Equal(t, "The route conflicts with HTTPRoute admin", res.Comments[0].Summary)
+	assert.Equal(t, "admin.example.org", res.Comments[0].Path)
+
+	res = check("HTTPRoute/gateway.networking.k8s.io/v1/apps/admin")
+	assert.Equal(t, scorecard.GradeWarning, res.Grade)
+	assert.Equal(t, "The route conflicts with HTTPRoute all-hostnames", res.Comments[0].Summary)
+}
@@ -15,3 +15,13 @@ func TestHorizontalPodAutoscalerHasNoTarget(t *testing.T) {
 	t.Parallel()
 	testExpectedScore(t, "hpa-has-no-target.yaml", "HorizontalPodAutoscaler has target", scorecard.GradeCritical)
 }
+
+func TestHorizontalPodAutoscalerV2TargetsDeployment(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "hpa-v2-targets-deployment.yaml", "HorizontalPodAutoscaler has target", scorecard.GradeAllOK)
+}
+
+func TestHorizontalPodAutoscalerV2HasNoTarget(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "hpa-v2-has-no-target.yaml", "HorizontalPodAutoscaler has target", scorecard.GradeCritical)
+}
@@ -0,0 +1,16 @@
+package internal
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ContainerFieldPath returns the field path of a container, relative to the pod template.
+// i is the index of the container in the list of all init containers followed by all containers.
+func ContainerFieldPath(spec corev1.PodSpec, i int) string {
+	if i < len(spec.InitContainers) {
+		return fmt.Sprintf("spec.initContainers[%d]", i)
+	}
+	return fmt.Sprintf("spec.containers[%d]", i-len(spec.InitContainers))
+}
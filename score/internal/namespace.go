@@ -0,0 +1,10 @@
+package internal
+
+// Namespace returns the namespace that an object with the namespace is created in.
+// Objects without a namespace are created in the default namespace, unless another namespace is set with kubectl.
+func Namespace(namespace string) string {
+	if namespace == "" {
+		return "default"
+	}
+	return namespace
+}
@@ -0,0 +1,25 @@
+package internal
+
+import ks "github.com/younes-bami/kube-score/domain"
+
+// PodAnnotations returns the annotations of the object and of its pod template, as annotations that opt in to or out
+// of a check can be set on either. The annotations of the object take precedence.
+func PodAnnotations(ps ks.PodSpecer) map[string]string {
+	return mergeMaps(ps.GetPodTemplateSpec().Annotations, ps.GetObjectMeta().Annotations)
+}
+
+// PodLabels returns the labels of the object and of its pod template. The labels of the object take precedence.
+func PodLabels(ps ks.PodSpecer) map[string]string {
+	return mergeMaps(ps.GetPodTemplateSpec().Labels, ps.GetObjectMeta().Labels)
+}
+
+// mergeMaps returns a map with the keys of all maps. Values of later maps take precedence.
+func mergeMaps(maps ...map[string]string) map[string]string {
+	res := make(map[string]string)
+	for _, m := range maps {
+		for k, v := range m {
+			res[k] = v
+		}
+	}
+	return res
+}
@@ -0,0 +1,100 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NormalizeCapability returns the name of the capability in upper case, and without the CAP_ prefix
+func NormalizeCapability(capability corev1.Capability) string {
+	return strings.TrimPrefix(strings.ToUpper(string(capability)), "CAP_")
+}
+
+// ContainerCapabilities returns the capabilities that are set in the security context of the container
+func ContainerCapabilities(container corev1.Container) corev1.Capabilities {
+	if container.SecurityContext == nil || container.SecurityContext.Capabilities == nil {
+		return corev1.Capabilities{}
+	}
+	return *container.SecurityContext.Capabilities
+}
+
+// DropsAllCapabilities returns true if ALL capabilities are dropped
+func DropsAllCapabilities(capabilities corev1.Capabilities) bool {
+	for _, capability := range capabilities.Drop {
+		if NormalizeCapability(capability) == "ALL" {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowPrivilegeEscalation returns the allowPrivilegeEscalation of the container, or nil if it is not set.
+// Privilege escalation is allowed if it is not set.
+func AllowPrivilegeEscalation(container corev1.Container) *bool {
+	if container.SecurityContext == nil {
+		return nil
+	}
+	return container.SecurityContext.AllowPrivilegeEscalation
+}
+
+// HostNamespace is a namespace of the node that the pod shares
+type HostNamespace struct {
+	// Name is "network", "PID" or "IPC"
+	Name      string
+	FieldPath string
+}
+
+// HostNamespaces returns the namespaces of the node that the pod shares
+func HostNamespaces(spec corev1.PodSpec) (res []HostNamespace) {
+	if spec.HostNetwork {
+		res = append(res, HostNamespace{"network", "spec.hostNetwork"})
+	}
+	if spec.HostPID {
+		res = append(res, HostNamespace{"PID", "spec.hostPID"})
+	}
+	if spec.HostIPC {
+		res = append(res, HostNamespace{"IPC", "spec.hostIPC"})
+	}
+	return
+}
+
+// HostPort is a port of a container that is bound on the node
+type HostPort struct {
+	Container string
+	FieldPath string
+	Port      int32
+}
+
+// HostPorts returns the ports of all containers that are bound on the node
+func HostPorts(spec corev1.PodSpec) (res []HostPort) {
+	allContainers := spec.InitContainers
+	allContainers = append(allContainers, spec.Containers...)
+	for i, container := range allContainers {
+		for j, port := range container.Ports {
+			if port.HostPort != 0 {
+				res = append(res, HostPort{container.Name, fmt.Sprintf("%s.ports[%d].hostPort", ContainerFieldPath(spec, i), j), port.HostPort})
+			}
+		}
+	}
+	return
+}
+
+// HostPathVolume is a volume that mounts a path from the node
+type HostPathVolume struct {
+	Volume corev1.Volume
+
+	// FieldPath is the path to the hostPath of the volume
+	FieldPath string
+}
+
+// HostPathVolumes returns the volumes of the pod that mount a path from the node
+func HostPathVolumes(spec corev1.PodSpec) (res []HostPathVolume) {
+	for i, volume := range spec.Volumes {
+		if volume.HostPath != nil {
+			res = append(res, HostPathVolume{volume, fmt.Sprintf("spec.volumes[%d].hostPath", i)})
+		}
+	}
+	return
+}
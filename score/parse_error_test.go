@@ -0,0 +1,65 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/parser"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+func TestParseErrorAbortsByDefault(t *testing.T) {
+	_, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("parse-error.yaml")},
+		KubernetesVersion: config.Semver{Major: 1, Minor: 18},
+	})
+	assert.Error(t, err)
+}
+
+func TestParseErrorTolerated(t *testing.T) {
+	sc, err := testScore(config.Configuration{
+		AllFiles:            []ks.NamedReader{testFile("parse-error.yaml")},
+		KubernetesVersion:   config.Semver{Major: 1, Minor: 18},
+		TolerateParseErrors: true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, sc, 4)
+
+	// Valid objects are still scored
+	assert.NotEmpty(t, sc["Service/v1//ok-service"].Checks)
+
+	// The line of syntax errors is known
+	syntax := sc["///testdata/parse-error.yaml:15"]
+	assert.Len(t, syntax.Checks, 1)
+	assert.Equal(t, "parse-error", syntax.Checks[0].Check.ID)
+	assert.Equal(t, scorecard.GradeCritical, syntax.Checks[0].Grade)
+	assert.Equal(t, ks.FileLocation{Name: "testdata/parse-error.yaml", Line: 15}, syntax.FileLocation)
+	assert.Contains(t, syntax.Checks[0].Comments[0].Summary, "mapping values are not allowed in this context")
+
+	// The type and name are detected for documents that are valid YAML
+	schema := sc["Deployment/apps/v1//broken-type"]
+	assert.Len(t, schema.Checks, 1)
+	assert.Equal(t, "parse-error", schema.Checks[0].Check.ID)
+	assert.Equal(t, ks.FileLocation{Name: "testdata/parse-error.yaml", Line: 17}, schema.FileLocation)
+
+	assert.Contains(t, sc, "///testdata/parse-error.yaml:24")
+}
+
+func TestParseErrorIgnored(t *testing.T) {
+	sc, err := testScore(config.Configuration{
+		AllFiles:            []ks.NamedReader{testFile("parse-error.yaml")},
+		KubernetesVersion:   config.Semver{Major: 1, Minor: 18},
+		TolerateParseErrors: true,
+		IgnoredTests:        map[string]struct{}{"parse-error": {}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, sc, 1)
+}
+
+func TestParseErrorIsListed(t *testing.T) {
+	allChecks := RegisterAllChecks(parser.Empty(), config.Configuration{})
+	assert.Contains(t, allChecks.All(), parseErrorCheck)
+}
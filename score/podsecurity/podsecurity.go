@@ -0,0 +1,401 @@
+package podsecurity
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/score/checks"
+	"github.com/younes-bami/kube-score/score/internal"
+	"github.com/younes-bami/kube-score/scorecard"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Register(allChecks *checks.Checks, cnf config.Configuration) {
+	level := cnf.PodSecurityLevel
+	if level == "" {
+		level = config.PodSecurityLevelRestricted
+	}
+
+	allChecks.RegisterOptionalPodCheck("Pod Security Standards", `Makes sure that all pods are allowed by the Pod Security Standards profile that is set with --pod-security-level ('restricted' if not set). Each violated control is reported separately.`, podSecurityStandards(level, cnf.KubernetesVersion))
+}
+
+// violation is a pod or container that is not allowed by a control
+type violation struct {
+	path      string
+	fieldPath string
+	summary   string
+}
+
+// control is a control of the Pod Security Standards, see https://kubernetes.io/docs/concepts/security/pod-security-standards/
+type control struct {
+	name        string
+	description string
+	restricted  bool
+	check       func(pod corev1.PodTemplateSpec, kubeVersion config.Semver) []violation
+}
+
+var controls = []control{
+	{name: "HostProcess", description: "Windows HostProcess containers have privileged access to the host. Remove securityContext.windowsOptions.hostProcess.", check: hostProcess},
+	{name: "Host Namespaces", description: "Sharing the host namespaces gives access to the processes and network of the node. Set hostNetwork, hostPID and hostIPC to false.", check: hostNamespaces},
+	{name: "Privileged Containers", description: "Privileged containers have almost the same access as processes that are running on the host. Set securityContext.privileged to false.", check: privileged},
+	{name: "Capabilities", description: "Only the capabilities that are granted to containers by default may be added: " + strings.Join(baselineCapabilities, ", ") + ".", check: baselineCapabilitiesControl},
+	{name: "HostPath Volumes", description: "HostPath volumes give access to the file system of the node. Use another volume type.", check: hostPathVolumes},
+	{name: "Host Ports", description: "Host ports expose the container on the network of the node. Remove the hostPort, and expose the pod with a Service.", check: hostPorts},
+	{name: "AppArmor", description: "The AppArmor profile may not be overridden to be unconfined. Set the profile to 'runtime/default' or 'localhost/<profile>'.", check: appArmor},
+	{name: "SELinux", description: "Only the container_t, container_init_t and container_kvm_t SELinux types may be set, and the SELinux user and role may not be set.", check: seLinux},
+	{name: "/proc Mount Type", description: "The default /proc masks reduce the attack surface. Remove securityContext.procMount, or set it to 'Default'.", check: procMount},
+	{name: "Seccomp", description: "The seccomp profile may not be Unconfined. Set securityContext.seccompProfile.type to 'RuntimeDefault' or 'Localhost'.", check: baselineSeccomp},
+	{name: "Sysctls", description: "Only sysctls that are namespaced and isolated from the node may be set.", check: sysctls},
+
+	{name: "Volume Types", restricted: true, description: "Only configMap, csi, downwardAPI, emptyDir, ephemeral, persistentVolumeClaim, projected and secret volumes are allowed.", check: volumeTypes},
+	{name: "Privilege Escalation", restricted: true, description: "Set securityContext.allowPrivilegeEscalation to false.", check: privilegeEscalation},
+	{name: "Running as Non-root", restricted: true, description: "Set securityContext.runAsNonRoot to true in the pod or in every container.", check: runAsNonRoot},
+	{name: "Running as Non-root user", restricted: true, description: "Set securityContext.runAsUser to a non-zero user, or remove it.", check: runAsNonRootUser},
+	{name: "Seccomp (restricted)", restricted: true, description: "Set securityContext.seccompProfile.type to 'RuntimeDefault' or 'Localhost' in the pod or in every container.", check: restrictedSeccomp},
+	{name: "Capabilities (restricted)", restricted: true, description: "Containers must drop ALL capabilities, and may only add back NET_BIND_SERVICE.", check: restrictedCapabilitiesControl},
+}
+
+// podSecurityStandards checks that the pod is allowed by all controls of the level
+func podSecurityStandards(level config.PodSecurityLevel, kubeVersion config.Semver) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		pod := ps.GetPodTemplateSpec()
+
+		hasViolation := false
+		for _, c := range controls {
+			if c.restricted && level != config.PodSecurityLevelRestricted {
+				continue
+			}
+			for _, v := range c.check(pod, kubeVersion) {
+				hasViolation = true
+				score.AddCommentWithFieldPath(v.path, v.fieldPath, c.name+": "+v.summary, c.description)
+			}
+		}
+
+		if hasViolation {
+			score.Grade = scorecard.GradeCritical
+		} else {
+			score.Grade = scorecard.GradeAllOK
+		}
+		return
+	}
+}
+
+// containers returns all init containers and containers, in the order expected by internal.ContainerFieldPath
+func containers(spec corev1.PodSpec) []corev1.Container {
+	allContainers := spec.InitContainers
+	return append(allContainers, spec.Containers...)
+}
+
+// isWindows returns true if the pod explicitly runs on Windows. Linux-only controls do not apply to these pods.
+func isWindows(spec corev1.PodSpec) bool {
+	return spec.OS != nil && spec.OS.Name == corev1.Windows
+}
+
+func hostProcess(pod corev1.PodTemplateSpec, _ config.Semver) (res []violation) {
+	spec := pod.Spec
+	if sc := spec.SecurityContext; sc != nil && sc.WindowsOptions != nil && sc.WindowsOptions.HostProcess != nil && *sc.WindowsOptions.HostProcess {
+		res = append(res, violation{fieldPath: "spec.securityContext.windowsOptions.hostProcess", summary: "The pod is a HostProcess pod"})
+	}
+	for i, container := range containers(spec) {
+		if sc := container.SecurityContext; sc != nil && sc.WindowsOptions != nil && sc.WindowsOptions.HostProcess != nil && *sc.WindowsOptions.HostProcess {
+			res = append(res, violation{container.Name, internal.ContainerFieldPath(spec, i) + ".securityContext.windowsOptions.hostProcess", "The container is a HostProcess container"})
+		}
+	}
+	return
+}
+
+func hostNamespaces(pod corev1.PodTemplateSpec, _ config.Semver) (res []violation) {
+	for _, ns := range internal.HostNamespaces(pod.Spec) {
+		res = append(res, violation{fieldPath: ns.FieldPath, summary: fmt.Sprintf("The pod uses the host %s namespace", ns.Name)})
+	}
+	return
+}
+
+func privileged(pod corev1.PodTemplateSpec, _ config.Semver) (res []violation) {
+	spec := pod.Spec
+	for i, container := range containers(spec) {
+		if sc := container.SecurityContext; sc != nil && sc.Privileged != nil && *sc.Privileged {
+			res = append(res, violation{container.Name, internal.ContainerFieldPath(spec, i) + ".securityContext.privileged", "The container is privileged"})
+		}
+	}
+	return
+}
+
+// baselineCapabilities are the capabilities that container runtimes grant by default
+var baselineCapabilities = []string{"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD", "NET_BIND_SERVICE", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT"}
+
+func isBaselineCapability(capability corev1.Capability) bool {
+	for _, c := range baselineCapabilities {
+		if internal.NormalizeCapability(capability) == c {
+			return true
+		}
+	}
+	return false
+}
+
+func baselineCapabilitiesControl(pod corev1.PodTemplateSpec, _ config.Semver) (res []violation) {
+	spec := pod.Spec
+	for i, container := range containers(spec) {
+		for j, capability := range internal.ContainerCapabilities(container).Add {
+			if !isBaselineCapability(capability) {
+				res = append(res, violation{container.Name, fmt.Sprintf("%s.securityContext.capabilities.add[%d]", internal.ContainerFieldPath(spec, i), j), fmt.Sprintf("The container adds the capability %s", capability)})
+			}
+		}
+	}
+	return
+}
+
+// restrictedCapabilitiesControl reports containers that do not drop ALL capabilities, or that add a default
+// capability other than NET_BIND_SERVICE. Other added capabilities are already reported by the baseline control.
+func restrictedCapabilitiesControl(pod corev1.PodTemplateSpec, _ config.Semver) (res []violation) {
+	spec := pod.Spec
+	if isWindows(spec) {
+		return
+	}
+	for i, container := range containers(spec) {
+		containerPath := internal.ContainerFieldPath(spec, i)
+
+		capabilities := internal.ContainerCapabilities(container)
+		if !internal.DropsAllCapabilities(capabilities) {
+			res = append(res, violation{container.Name, containerPath + ".securityContext.capabilities.drop", "The container does not drop ALL capabilities"})
+		}
+
+		for j, capability := range capabilities.Add {
+			if internal.NormalizeCapability(capability) != "NET_BIND_SERVICE" && isBaselineCapability(capability) {
+				res = append(res, violation{container.Name, fmt.Sprintf("%s.securityContext.capabilities.add[%d]", containerPath, j), fmt.Sprintf("The container adds the capability %s", capability)})
+			}
+		}
+	}
+	return
+}
+
+func hostPathVolumes(pod corev1.PodTemplateSpec, _ config.Semver) (res []violation) {
+	for _, volume := range internal.HostPathVolumes(pod.Spec) {
+		res = append(res, violation{fieldPath: volume.FieldPath, summary: fmt.Sprintf("The volume %s is a hostPath volume", volume.Volume.Name)})
+	}
+	return
+}
+
+func hostPorts(pod corev1.PodTemplateSpec, _ config.Semver) (res []violation) {
+	for _, port := range internal.HostPorts(pod.Spec) {
+		res = append(res, violation{port.Container, port.FieldPath, fmt.Sprintf("The container uses the host port %d", port.Port)})
+	}
+	return
+}
+
+const appArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
+func appArmor(pod corev1.PodTemplateSpec, _ config.Semver) (res []violation) {
+	var keys []string
+	for key := range pod.Annotations {
+		if strings.HasPrefix(key, appArmorAnnotationPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := pod.Annotations[key]
+		if value != "runtime/default" && !strings.HasPrefix(value, "localhost/") {
+			containerName := strings.TrimPrefix(key, appArmorAnnotationPrefix)
+			res = append(res, violation{containerName, "metadata.annotations", fmt.Sprintf("The container has the AppArmor profile %q", value)})
+		}
+	}
+	return
+}
+
+var allowedSELinuxTypes = map[string]struct{}{
+	"":                 {},
+	"container_t":      {},
+	"container_init_t": {},
+	"container_kvm_t":  {},
+}
+
+func seLinuxViolations(path, fieldPath string, opts *corev1.SELinuxOptions) (res []violation) {
+	if opts == nil {
+		return
+	}
+	if _, ok := allowedSELinuxTypes[opts.Type]; !ok {
+		res = append(res, violation{path, fieldPath + ".type", fmt.Sprintf("The SELinux type %s is not allowed", opts.Type)})
+	}
+	if opts.User != "" {
+		res = append(res, violation{path, fieldPath + ".user", "The SELinux user is set"})
+	}
+	if opts.Role != "" {
+		res = append(res, violation{path, fieldPath + ".role", "The SELinux role is set"})
+	}
+	return
+}
+
+func seLinux(pod corev1.PodTemplateSpec, _ config.Semver) (res []violation) {
+	spec := pod.Spec
+	if spec.SecurityContext != nil {
+		res = append(res, seLinuxViolations("", "spec.securityContext.seLinuxOptions", spec.SecurityContext.SELinuxOptions)...)
+	}
+	for i, container := range containers(spec) {
+		if container.SecurityContext != nil {
+			res = append(res, seLinuxViolations(container.Name, internal.ContainerFieldPath(spec, i)+".securityContext.seLinuxOptions", container.SecurityContext.SELinuxOptions)...)
+		}
+	}
+	return
+}
+
+func procMount(pod corev1.PodTemplateSpec, _ config.Semver) (res []violation) {
+	spec := pod.Spec
+	for i, container := range containers(spec) {
+		if sc := container.SecurityContext; sc != nil && sc.ProcMount != nil && *sc.ProcMount != corev1.DefaultProcMount {
+			res = append(res, violation{container.Name, internal.ContainerFieldPath(spec, i) + ".securityContext.procMount", fmt.Sprintf("The container uses the %s /proc mount type", *sc.ProcMount)})
+		}
+	}
+	return
+}
+
+func baselineSeccomp(pod corev1.PodTemplateSpec, _ config.Semver) (res []violation) {
+	spec := pod.Spec
+	if sc := spec.SecurityContext; sc != nil && sc.SeccompProfile != nil && sc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+		res = append(res, violation{fieldPath: "spec.securityContext.seccompProfile.type", summary: "The pod has an Unconfined seccomp profile"})
+	}
+	for i, container := range containers(spec) {
+		if sc := container.SecurityContext; sc != nil && sc.SeccompProfile != nil && sc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			res = append(res, violation{container.Name, internal.ContainerFieldPath(spec, i) + ".securityContext.seccompProfile.type", "The container has an Unconfined seccomp profile"})
+		}
+	}
+	return
+}
+
+// restrictedSeccomp reports containers that do not have a seccomp profile set, either on the container or on the pod.
+// Unconfined profiles are already reported by the baseline control.
+func restrictedSeccomp(pod corev1.PodTemplateSpec, _ config.Semver) (res []violation) {
+	spec := pod.Spec
+	if isWindows(spec) {
+		return
+	}
+	podHasProfile := spec.SecurityContext != nil && spec.SecurityContext.SeccompProfile != nil
+	for i, container := range containers(spec) {
+		if container.SecurityContext != nil && container.SecurityContext.SeccompProfile != nil {
+			continue
+		}
+		if !podHasProfile {
+			res = append(res, violation{container.Name, internal.ContainerFieldPath(spec, i) + ".securityContext.seccompProfile", "The container has no seccomp profile"})
+		}
+	}
+	return
+}
+
+// safeSysctls are the sysctls that are allowed by the baseline profile, and the Kubernetes version that they were allowed in
+var safeSysctls = map[string]config.Semver{
+	"kernel.shm_rmid_forced":              {Major: 1, Minor: 0},
+	"net.ipv4.ip_local_port_range":        {Major: 1, Minor: 0},
+	"net.ipv4.ip_unprivileged_port_start": {Major: 1, Minor: 0},
+	"net.ipv4.tcp_syncookies":             {Major: 1, Minor: 0},
+	"net.ipv4.ping_group_range":           {Major: 1, Minor: 0},
+	"net.ipv4.ip_local_reserved_ports":    {Major: 1, Minor: 27},
+	"net.ipv4.tcp_keepalive_time":         {Major: 1, Minor: 29},
+	"net.ipv4.tcp_fin_timeout":            {Major: 1, Minor: 29},
+	"net.ipv4.tcp_keepalive_intvl":        {Major: 1, Minor: 29},
+	"net.ipv4.tcp_keepalive_probes":       {Major: 1, Minor: 29},
+}
+
+func sysctls(pod corev1.PodTemplateSpec, kubeVersion config.Semver) (res []violation) {
+	if pod.Spec.SecurityContext == nil {
+		return
+	}
+	for i, sysctl := range pod.Spec.SecurityContext.Sysctls {
+		since, ok := safeSysctls[sysctl.Name]
+		if !ok || kubeVersion.LessThan(since) {
+			res = append(res, violation{fieldPath: fmt.Sprintf("spec.securityContext.sysctls[%d].name", i), summary: fmt.Sprintf("The sysctl %s is not allowed", sysctl.Name)})
+		}
+	}
+	return
+}
+
+var allowedVolumeTypes = map[string]struct{}{
+	"configMap":             {},
+	"csi":                   {},
+	"downwardAPI":           {},
+	"emptyDir":              {},
+	"ephemeral":             {},
+	"persistentVolumeClaim": {},
+	"projected":             {},
+	"secret":                {},
+}
+
+// volumeType returns the name of the source that is set on the volume, such as "hostPath"
+func volumeType(volume corev1.Volume) string {
+	source := reflect.ValueOf(volume.VolumeSource)
+	for i := 0; i < source.NumField(); i++ {
+		if !source.Field(i).IsNil() {
+			tag := source.Type().Field(i).Tag.Get("json")
+			return strings.Split(tag, ",")[0]
+		}
+	}
+	return ""
+}
+
+func volumeTypes(pod corev1.PodTemplateSpec, _ config.Semver) (res []violation) {
+	for i, volume := range pod.Spec.Volumes {
+		typ := volumeType(volume)
+		if typ == "" {
+			// A volume without a source is an emptyDir
+			continue
+		}
+		if _, ok := allowedVolumeTypes[typ]; !ok {
+			res = append(res, violation{fieldPath: fmt.Sprintf("spec.volumes[%d].%s", i, typ), summary: fmt.Sprintf("The volume %s has the type %s", volume.Name, typ)})
+		}
+	}
+	return
+}
+
+func privilegeEscalation(pod corev1.PodTemplateSpec, _ config.Semver) (res []violation) {
+	spec := pod.Spec
+	if isWindows(spec) {
+		return
+	}
+	for i, container := range containers(spec) {
+		if allowed := internal.AllowPrivilegeEscalation(container); allowed == nil || *allowed {
+			res = append(res, violation{container.Name, internal.ContainerFieldPath(spec, i) + ".securityContext.allowPrivilegeEscalation", "The container allows privilege escalation"})
+		}
+	}
+	return
+}
+
+func runAsNonRoot(pod corev1.PodTemplateSpec, _ config.Semver) (res []violation) {
+	spec := pod.Spec
+	podRunAsNonRoot := false
+	if sc := spec.SecurityContext; sc != nil && sc.RunAsNonRoot != nil {
+		if !*sc.RunAsNonRoot {
+			res = append(res, violation{fieldPath: "spec.securityContext.runAsNonRoot", summary: "The pod sets runAsNonRoot to false"})
+		}
+		podRunAsNonRoot = *sc.RunAsNonRoot
+	}
+	for i, container := range containers(spec) {
+		fieldPath := internal.ContainerFieldPath(spec, i) + ".securityContext.runAsNonRoot"
+		if sc := container.SecurityContext; sc != nil && sc.RunAsNonRoot != nil {
+			if !*sc.RunAsNonRoot {
+				res = append(res, violation{container.Name, fieldPath, "The container sets runAsNonRoot to false"})
+			}
+			continue
+		}
+		if !podRunAsNonRoot {
+			res = append(res, violation{container.Name, fieldPath, "The container is not required to run as a non-root user"})
+		}
+	}
+	return
+}
+
+func runAsNonRootUser(pod corev1.PodTemplateSpec, _ config.Semver) (res []violation) {
+	spec := pod.Spec
+	if sc := spec.SecurityContext; sc != nil && sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+		res = append(res, violation{fieldPath: "spec.securityContext.runAsUser", summary: "The pod runs as the root user"})
+	}
+	for i, container := range containers(spec) {
+		if sc := container.SecurityContext; sc != nil && sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+			res = append(res, violation{container.Name, internal.ContainerFieldPath(spec, i) + ".securityContext.runAsUser", "The container runs as the root user"})
+		}
+	}
+	return
+}
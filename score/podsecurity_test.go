@@ -0,0 +1,109 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+func podSecurityConfig(file string, level config.PodSecurityLevel) config.Configuration {
+	return config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile(file)},
+		EnabledOptionalTests: map[string]struct{}{"pod-security-standards": {}},
+		KubernetesVersion:    config.Semver{Major: 1, Minor: 27},
+		PodSecurityLevel:     level,
+	}
+}
+
+func TestPodSecurityStandardsNotRunByDefault(t *testing.T) {
+	t.Parallel()
+	skipped := wasSkipped(t, config.Configuration{
+		AllFiles: []ks.NamedReader{testFile("pod-security-baseline-violations.yaml")},
+	}, "Pod Security Standards")
+	assert.True(t, skipped)
+}
+
+func TestPodSecurityStandardsBaselineViolations(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, podSecurityConfig("pod-security-baseline-violations.yaml", config.PodSecurityLevelBaseline), "Pod Security Standards", scorecard.GradeCritical)
+
+	var fieldPaths []string
+	for _, c := range comments {
+		fieldPaths = append(fieldPaths, c.FieldPath)
+	}
+	assert.Equal(t, []string{
+		"Host Namespaces: The pod uses the host network namespace",
+		"Host Namespaces: The pod uses the host PID namespace",
+		"Privileged Containers: The container is privileged",
+		"Capabilities: The container adds the capability SYS_ADMIN",
+		"HostPath Volumes: The volume host is a hostPath volume",
+		"Host Ports: The container uses the host port 8080",
+		`AppArmor: The container has the AppArmor profile "unconfined"`,
+		"SELinux: The SELinux type spc_t is not allowed",
+		"/proc Mount Type: The container uses the Unmasked /proc mount type",
+		"Seccomp: The container has an Unconfined seccomp profile",
+		"Sysctls: The sysctl kernel.msgmax is not allowed",
+	}, summariesOf(comments))
+	assert.Equal(t, []string{
+		"spec.hostNetwork",
+		"spec.hostPID",
+		"spec.containers[0].securityContext.privileged",
+		"spec.containers[0].securityContext.capabilities.add[0]",
+		"spec.volumes[0].hostPath",
+		"spec.containers[0].ports[0].hostPort",
+		"metadata.annotations",
+		"spec.containers[0].securityContext.seLinuxOptions.type",
+		"spec.containers[0].securityContext.procMount",
+		"spec.containers[0].securityContext.seccompProfile.type",
+		"spec.securityContext.sysctls[0].name",
+	}, fieldPaths)
+}
+
+func TestPodSecurityStandardsSysctlsKubernetesVersion(t *testing.T) {
+	t.Parallel()
+	cnf := podSecurityConfig("pod-security-baseline-violations.yaml", config.PodSecurityLevelBaseline)
+	cnf.KubernetesVersion = config.Semver{Major: 1, Minor: 26}
+	comments := testExpectedScoreWithConfig(t, cnf, "Pod Security Standards", scorecard.GradeCritical)
+	assert.Contains(t, summariesOf(comments), "Sysctls: The sysctl net.ipv4.ip_local_reserved_ports is not allowed")
+}
+
+func TestPodSecurityStandardsBaselineAllowed(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, podSecurityConfig("pod-security-baseline-only.yaml", config.PodSecurityLevelBaseline), "Pod Security Standards", scorecard.GradeAllOK)
+}
+
+func TestPodSecurityStandardsRestrictedViolations(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, podSecurityConfig("pod-security-baseline-only.yaml", config.PodSecurityLevelRestricted), "Pod Security Standards", scorecard.GradeCritical)
+	assert.Equal(t, []string{
+		"Volume Types: The volume data has the type nfs",
+		"Privilege Escalation: The container allows privilege escalation",
+		"Running as Non-root: The container is not required to run as a non-root user",
+		"Seccomp (restricted): The container has no seccomp profile",
+		"Capabilities (restricted): The container does not drop ALL capabilities",
+		"Capabilities (restricted): The container adds the capability CHOWN",
+	}, summariesOf(comments))
+	assert.Equal(t, "spec.template.spec.volumes[0].nfs", comments[0].FieldPath)
+	assert.Equal(t, "app", comments[1].Path)
+}
+
+func TestPodSecurityStandardsRestrictedAllowed(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, podSecurityConfig("pod-security-restricted.yaml", config.PodSecurityLevelRestricted), "Pod Security Standards", scorecard.GradeAllOK)
+}
+
+func TestPodSecurityStandardsDefaultsToRestricted(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, podSecurityConfig("pod-security-baseline-only.yaml", ""), "Pod Security Standards", scorecard.GradeCritical)
+}
+
+func summariesOf(comments []scorecard.TestScoreComment) []string {
+	var res []string
+	for _, c := range comments {
+		res = append(res, c.Summary)
+	}
+	return res
+}
@@ -0,0 +1,78 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+var argoRolloutPodTemplate = config.PodTemplate{
+	APIVersion: "argoproj.io/v1alpha1",
+	Kind:       "Rollout",
+	Template:   "spec.template",
+	Replicas:   "spec.replicas",
+	Selector:   "spec.selector",
+}
+
+func TestCustomPodTemplate(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("argo-rollout.yaml")},
+		KubernetesVersion: config.Semver{Major: 1, Minor: 18},
+		PodTemplates:      []config.PodTemplate{argoRolloutPodTemplate},
+	}, "Container Image Tag", scorecard.GradeCritical)
+
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "spec.template.spec.containers[0].image", comments[0].FieldPath)
+	assert.Equal(t, 18, comments[0].FileLocation.Line)
+}
+
+func TestCustomPodTemplateNotConfigured(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("argo-rollout.yaml")},
+		KubernetesVersion: config.Semver{Major: 1, Minor: 18},
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, sc, "Rollout/argoproj.io/v1alpha1/prod/app")
+
+	for _, c := range sc["Rollout/argoproj.io/v1alpha1/prod/app"].Checks {
+		assert.NotEqual(t, "container-image-tag", c.Check.ID)
+	}
+}
+
+func TestCustomPodTemplateWorkloadChecks(t *testing.T) {
+	t.Parallel()
+	// The files are read when scored, and are opened again for every run
+	cnf := func() config.Configuration {
+		return config.Configuration{
+			AllFiles:          []ks.NamedReader{testFile("argo-rollout.yaml")},
+			KubernetesVersion: config.Semver{Major: 1, Minor: 18},
+			PodTemplates:      []config.PodTemplate{argoRolloutPodTemplate},
+		}
+	}
+	testExpectedScoreWithConfig(t, cnf(), "Workload Pod Selector labels match template metadata labels", scorecard.GradeAllOK)
+	comments := testExpectedScoreWithConfig(t, cnf(), "Workload has PodDisruptionBudget", scorecard.GradeCritical)
+	assert.Equal(t, "No matching PodDisruptionBudget was found", comments[0].Summary)
+	assert.True(t, wasSkipped(t, cnf(), "Workload targeted by HPA does not have replicas configured"))
+}
+
+func TestCustomPodTemplateWorkloadHPAAndPDB(t *testing.T) {
+	t.Parallel()
+	cnf := func() config.Configuration {
+		return config.Configuration{
+			AllFiles:          []ks.NamedReader{testFile("argo-rollout-hpa-pdb.yaml")},
+			KubernetesVersion: config.Semver{Major: 1, Minor: 23},
+			PodTemplates:      []config.PodTemplate{argoRolloutPodTemplate},
+		}
+	}
+	comments := testExpectedScoreWithConfig(t, cnf(), "Workload targeted by HPA does not have replicas configured", scorecard.GradeCritical)
+	assert.Equal(t, "The Rollout is targeted by a HPA, but a static replica count is configured", comments[0].Summary)
+	comments = testExpectedScoreWithConfig(t, cnf(), "Workload Pod Selector labels match template metadata labels", scorecard.GradeCritical)
+	assert.Equal(t, "Rollout selector labels not matching template metadata labels", comments[0].Summary)
+	testExpectedScoreWithConfig(t, cnf(), "Workload has PodDisruptionBudget", scorecard.GradeAllOK)
+}
@@ -0,0 +1,252 @@
+package rbac
+
+import (
+	"fmt"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/score/checks"
+	"github.com/younes-bami/kube-score/score/internal"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+func Register(allChecks *checks.Checks, roles ks.Roles, serviceAccounts ks.ServiceAccounts) {
+	allChecks.RegisterRoleCheck("Role Wildcard Permissions", `Makes sure that Roles and ClusterRoles do not use wildcards in verbs, resources, apiGroups or nonResourceURLs`, roleWildcardPermissions)
+	allChecks.RegisterRoleCheck("Role Secret Access", `Makes sure that Roles and ClusterRoles do not allow reading Secrets with get, list or watch. Allowing get on specific resourceNames is accepted.`, roleSecretAccess)
+	allChecks.RegisterRoleCheck("Role Privilege Escalation", `Makes sure that Roles and ClusterRoles do not allow the escalate, bind or impersonate verbs`, rolePrivilegeEscalation)
+	allChecks.RegisterRoleCheck("Role Pod Exec", `Makes sure that Roles and ClusterRoles do not allow executing commands in, or attaching to, pods`, rolePodExec)
+
+	allChecks.RegisterRoleBindingCheck("RoleBinding Anonymous Subjects", `Makes sure that RoleBindings and ClusterRoleBindings do not grant permissions to system:anonymous, system:unauthenticated or system:authenticated`, roleBindingAnonymousSubjects)
+	allChecks.RegisterOptionalRoleBindingCheck("RoleBinding Role Exists", `Makes sure that RoleBindings and ClusterRoleBindings refer to a Role or ClusterRole in the input. The default ClusterRoles of Kubernetes are assumed to exist. Enable it when the roles are deployed together with the bindings.`, roleBindingRoleExists(roles.Roles()))
+	allChecks.RegisterOptionalRoleBindingCheck("RoleBinding Subjects Exist", `Makes sure that the ServiceAccounts that RoleBindings and ClusterRoleBindings grant permissions to are in the input. Enable it when the ServiceAccounts are deployed together with the bindings.`, roleBindingSubjectsExist(serviceAccounts.ServiceAccounts()))
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesResource returns true if the rule applies to the resource in the core API group
+func matchesResource(rule rbacv1.PolicyRule, resource string) bool {
+	return (contains(rule.APIGroups, "") || contains(rule.APIGroups, "*")) &&
+		(contains(rule.Resources, resource) || contains(rule.Resources, "*"))
+}
+
+// grantsVerb returns true if the rule allows the verb, either explicitly or with a wildcard
+func grantsVerb(rule rbacv1.PolicyRule, verb string) bool {
+	return contains(rule.Verbs, verb) || contains(rule.Verbs, "*")
+}
+
+// roleWildcardPermissions checks that no rule uses wildcards
+func roleWildcardPermissions(role ks.Role) (score scorecard.TestScore, err error) {
+	for i, rule := range role.Rules() {
+		fields := []struct {
+			name   string
+			values []string
+		}{
+			{"verbs", rule.Verbs},
+			{"apiGroups", rule.APIGroups},
+			{"resources", rule.Resources},
+			{"nonResourceURLs", rule.NonResourceURLs},
+		}
+		for _, field := range fields {
+			for j, value := range field.values {
+				if value == "*" || strings.HasSuffix(value, "/*") {
+					score.AddCommentWithFieldPath("", fmt.Sprintf("rules[%d].%s[%d]", i, field.name, j), fmt.Sprintf("The rule uses a wildcard in %s", field.name), "Wildcards grant access to everything that matches, including resources and verbs that are added in the future. List the permissions that are needed explicitly.")
+				}
+			}
+		}
+	}
+
+	if len(score.Comments) > 0 {
+		score.Grade = scorecard.GradeCritical
+	} else {
+		score.Grade = scorecard.GradeAllOK
+	}
+	return
+}
+
+// roleSecretAccess checks that no rule allows reading Secrets. get is allowed if the rule is limited to resourceNames,
+// as list and watch can not be limited by name.
+func roleSecretAccess(role ks.Role) (score scorecard.TestScore, err error) {
+	for i, rule := range role.Rules() {
+		if !matchesResource(rule, "secrets") {
+			continue
+		}
+		var verbs []string
+		for _, verb := range []string{"get", "list", "watch"} {
+			if verb == "get" && len(rule.ResourceNames) > 0 {
+				continue
+			}
+			if grantsVerb(rule, verb) {
+				verbs = append(verbs, verb)
+			}
+		}
+		if len(verbs) > 0 {
+			score.AddCommentWithFieldPath("", fmt.Sprintf("rules[%d]", i), fmt.Sprintf("The rule allows %s on secrets", strings.Join(verbs, ", ")), "Reading Secrets gives access to all credentials in the namespace, including ServiceAccount tokens. Limit get to the Secrets that are needed with resourceNames, or mount the Secrets in the pod instead.")
+		}
+	}
+
+	if len(score.Comments) > 0 {
+		score.Grade = scorecard.GradeCritical
+	} else {
+		score.Grade = scorecard.GradeAllOK
+	}
+	return
+}
+
+// rolePrivilegeEscalation checks that no rule explicitly allows verbs that can be used to gain more permissions.
+// Wildcard verbs are reported by roleWildcardPermissions.
+func rolePrivilegeEscalation(role ks.Role) (score scorecard.TestScore, err error) {
+	descriptions := map[string]string{
+		"escalate":    "The escalate verb allows creating and updating roles with permissions that the subject does not have.",
+		"bind":        "The bind verb allows binding roles with permissions that the subject does not have.",
+		"impersonate": "The impersonate verb allows acting as other users, groups or ServiceAccounts, with all of their permissions.",
+	}
+
+	for i, rule := range role.Rules() {
+		for j, verb := range rule.Verbs {
+			if description, ok := descriptions[verb]; ok {
+				score.AddCommentWithFieldPath("", fmt.Sprintf("rules[%d].verbs[%d]", i, j), fmt.Sprintf("The rule allows %s on %s", verb, strings.Join(rule.Resources, ", ")), description)
+			}
+		}
+	}
+
+	if len(score.Comments) > 0 {
+		score.Grade = scorecard.GradeCritical
+	} else {
+		score.Grade = scorecard.GradeAllOK
+	}
+	return
+}
+
+// rolePodExec checks that no rule allows executing commands in, or attaching to, pods.
+// Both create and get are checked, as get was used by older clients.
+func rolePodExec(role ks.Role) (score scorecard.TestScore, err error) {
+	for i, rule := range role.Rules() {
+		for _, subresource := range []string{"pods/exec", "pods/attach"} {
+			if !contains(rule.APIGroups, "") && !contains(rule.APIGroups, "*") {
+				continue
+			}
+			if !contains(rule.Resources, subresource) && !contains(rule.Resources, "pods/*") && !contains(rule.Resources, "*") {
+				continue
+			}
+			if grantsVerb(rule, "create") || grantsVerb(rule, "get") {
+				score.AddCommentWithFieldPath("", fmt.Sprintf("rules[%d]", i), fmt.Sprintf("The rule allows %s", subresource), "Executing commands in pods gives access to everything that the pod has access to, such as mounted Secrets and ServiceAccount tokens. Only grant this to break-glass roles.")
+			}
+		}
+	}
+
+	if len(score.Comments) > 0 {
+		score.Grade = scorecard.GradeWarning
+	} else {
+		score.Grade = scorecard.GradeAllOK
+	}
+	return
+}
+
+// roleBindingAnonymousSubjects checks that the binding does not grant permissions to unauthenticated users, or to all users
+func roleBindingAnonymousSubjects(binding ks.RoleBinding) (score scorecard.TestScore, err error) {
+	for i, subject := range binding.Subjects() {
+		if (subject.Kind == rbacv1.UserKind && subject.Name == "system:anonymous") ||
+			(subject.Kind == rbacv1.GroupKind && (subject.Name == "system:unauthenticated" || subject.Name == "system:authenticated")) {
+			score.AddCommentWithFieldPath("", fmt.Sprintf("subjects[%d].name", i), fmt.Sprintf("The binding grants permissions to %s", subject.Name), "system:anonymous and system:unauthenticated are all unauthenticated requests, and system:authenticated is every user and ServiceAccount in the cluster. Bind the role to the subjects that need it.")
+		}
+	}
+
+	if len(score.Comments) > 0 {
+		score.Grade = scorecard.GradeCritical
+	} else {
+		score.Grade = scorecard.GradeAllOK
+	}
+	return
+}
+
+// defaultClusterRoles are the user-facing ClusterRoles that are created by Kubernetes.
+// ClusterRoles with the system: prefix are also created by Kubernetes.
+var defaultClusterRoles = map[string]struct{}{
+	"cluster-admin": {},
+	"admin":         {},
+	"edit":          {},
+	"view":          {},
+}
+
+// roleBindingRoleExists checks that the role that is referenced by the binding is in the input
+func roleBindingRoleExists(roles []ks.Role) func(ks.RoleBinding) (scorecard.TestScore, error) {
+	return func(binding ks.RoleBinding) (score scorecard.TestScore, err error) {
+		ref := binding.RoleRef()
+		bindingKind := binding.GetTypeMeta().Kind
+		namespace := binding.GetObjectMeta().Namespace
+
+		if bindingKind == "ClusterRoleBinding" && ref.Kind != "ClusterRole" {
+			score.Grade = scorecard.GradeCritical
+			score.AddCommentWithFieldPath("", "roleRef.kind", "The ClusterRoleBinding refers to a "+ref.Kind, "ClusterRoleBindings can only refer to ClusterRoles.")
+			return
+		}
+
+		if ref.Kind == "ClusterRole" {
+			if _, ok := defaultClusterRoles[ref.Name]; ok || strings.HasPrefix(ref.Name, "system:") {
+				score.Grade = scorecard.GradeAllOK
+				return
+			}
+		}
+
+		for _, role := range roles {
+			if role.GetTypeMeta().Kind != ref.Kind || role.GetObjectMeta().Name != ref.Name {
+				continue
+			}
+			if ref.Kind == "Role" && internal.Namespace(role.GetObjectMeta().Namespace) != internal.Namespace(namespace) {
+				continue
+			}
+			score.Grade = scorecard.GradeAllOK
+			return
+		}
+
+		score.Grade = scorecard.GradeCritical
+		score.AddCommentWithFieldPath("", "roleRef.name", fmt.Sprintf("The %s %s does not exist", ref.Kind, ref.Name), fmt.Sprintf("No %s with this name was found in the input. The binding does not grant any permissions until the role is created.", ref.Kind))
+		return
+	}
+}
+
+// roleBindingSubjectsExist checks that all ServiceAccounts that are subjects of the binding are in the input.
+// Users and groups are managed outside of the cluster, and are not checked. Objects without a namespace are in the
+// default namespace.
+func roleBindingSubjectsExist(serviceAccounts []ks.ServiceAccount) func(ks.RoleBinding) (scorecard.TestScore, error) {
+	return func(binding ks.RoleBinding) (score scorecard.TestScore, err error) {
+		for i, subject := range binding.Subjects() {
+			if subject.Kind != rbacv1.ServiceAccountKind || subject.Name == "default" {
+				continue
+			}
+
+			namespace := subject.Namespace
+			if namespace == "" {
+				namespace = binding.GetObjectMeta().Namespace
+			}
+
+			found := false
+			for _, s := range serviceAccounts {
+				sa := s.ServiceAccount()
+				if internal.Namespace(sa.Namespace) == internal.Namespace(namespace) && sa.Name == subject.Name {
+					found = true
+					break
+				}
+			}
+			if !found {
+				score.AddCommentWithFieldPath("", fmt.Sprintf("subjects[%d].name", i), fmt.Sprintf("The ServiceAccount %s does not exist", subject.Name), "No ServiceAccount with this name was found in the namespace of the subject.")
+			}
+		}
+
+		if len(score.Comments) > 0 {
+			score.Grade = scorecard.GradeCritical
+		} else {
+			score.Grade = scorecard.GradeAllOK
+		}
+		return
+	}
+}
@@ -0,0 +1,137 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+// rbacConfig returns a new configuration that scores rbac.yaml with the optional checks enabled
+func rbacConfig() config.Configuration {
+	return config.Configuration{
+		AllFiles: []ks.NamedReader{testFile("rbac.yaml")},
+		EnabledOptionalTests: map[string]struct{}{
+			"rolebinding-role-exists":    {},
+			"rolebinding-subjects-exist": {},
+		},
+	}
+}
+
+const (
+	rbacReader          = "Role/rbac.authorization.k8s.io/v1/apps/reader"
+	rbacAdminAll        = "ClusterRole/rbac.authorization.k8s.io/v1//admin-all"
+	rbacConfigMapReader = "ClusterRole/rbac.authorization.k8s.io/v1//configmap-reader"
+)
+
+func TestRoleWildcardPermissions(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, rbacConfig(), rbacReader, "role-wildcard-permissions").Grade)
+
+	res := testObjectCheck(t, rbacConfig(), rbacAdminAll, "role-wildcard-permissions")
+	assert.Equal(t, scorecard.GradeCritical, res.Grade)
+	assert.Len(t, res.Comments, 3)
+	assert.Equal(t, "rules[0].verbs[0]", res.Comments[0].FieldPath)
+	assert.Equal(t, "The rule uses a wildcard in verbs", res.Comments[0].Summary)
+	assert.Equal(t, "rules[0].apiGroups[0]", res.Comments[1].FieldPath)
+	assert.Equal(t, "rules[0].resources[0]", res.Comments[2].FieldPath)
+}
+
+func TestRoleSecretAccess(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, rbacConfig(), rbacConfigMapReader, "role-secret-access").Grade)
+
+	res := testObjectCheck(t, rbacConfig(), rbacReader, "role-secret-access")
+	assert.Equal(t, scorecard.GradeCritical, res.Grade)
+	assert.Len(t, res.Comments, 1)
+	assert.Equal(t, "The rule allows get, list on secrets", res.Comments[0].Summary)
+	assert.Equal(t, "rules[0]", res.Comments[0].FieldPath)
+	assert.Equal(t, 13, res.Comments[0].FileLocation.Line)
+
+	res = testObjectCheck(t, rbacConfig(), rbacAdminAll, "role-secret-access")
+	assert.Equal(t, scorecard.GradeCritical, res.Grade)
+	assert.Equal(t, "The rule allows get, list, watch on secrets", res.Comments[0].Summary)
+}
+
+func TestRolePrivilegeEscalation(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, rbacConfig(), rbacReader, "role-privilege-escalation").Grade)
+
+	res := testObjectCheck(t, rbacConfig(), rbacAdminAll, "role-privilege-escalation")
+	assert.Equal(t, scorecard.GradeCritical, res.Grade)
+	assert.Len(t, res.Comments, 2)
+	assert.Equal(t, "The rule allows bind on clusterroles", res.Comments[0].Summary)
+	assert.Equal(t, "rules[1].verbs[1]", res.Comments[1].FieldPath)
+}
+
+func TestRolePodExec(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, rbacConfig(), rbacConfigMapReader, "role-pod-exec").Grade)
+
+	res := testObjectCheck(t, rbacConfig(), rbacReader, "role-pod-exec")
+	assert.Equal(t, scorecard.GradeWarning, res.Grade)
+	assert.Len(t, res.Comments, 1)
+	assert.Equal(t, "The rule allows pods/exec", res.Comments[0].Summary)
+	assert.Equal(t, "rules[2]", res.Comments[0].FieldPath)
+}
+
+func TestRoleBindingAnonymousSubjects(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, rbacConfig(), "RoleBinding/rbac.authorization.k8s.io/v1/apps/missing-role", "rolebinding-anonymous-subjects").Grade)
+
+	res := testObjectCheck(t, rbacConfig(), "RoleBinding/rbac.authorization.k8s.io/v1/apps/reader", "rolebinding-anonymous-subjects")
+	assert.Equal(t, scorecard.GradeCritical, res.Grade)
+	assert.Len(t, res.Comments, 1)
+	assert.Equal(t, "subjects[2].name", res.Comments[0].FieldPath)
+
+	res = testObjectCheck(t, rbacConfig(), "ClusterRoleBinding/rbac.authorization.k8s.io/v1//view", "rolebinding-anonymous-subjects")
+	assert.Equal(t, scorecard.GradeCritical, res.Grade)
+	assert.Equal(t, "The binding grants permissions to system:anonymous", res.Comments[0].Summary)
+}
+
+func TestRoleBindingRoleExists(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, rbacConfig(), "RoleBinding/rbac.authorization.k8s.io/v1/apps/reader", "rolebinding-role-exists").Grade)
+
+	// view is a default ClusterRole
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, rbacConfig(), "ClusterRoleBinding/rbac.authorization.k8s.io/v1//view", "rolebinding-role-exists").Grade)
+
+	res := testObjectCheck(t, rbacConfig(), "RoleBinding/rbac.authorization.k8s.io/v1/apps/missing-role", "rolebinding-role-exists")
+	assert.Equal(t, scorecard.GradeCritical, res.Grade)
+	assert.Equal(t, "The ClusterRole missing does not exist", res.Comments[0].Summary)
+	assert.Equal(t, "roleRef.name", res.Comments[0].FieldPath)
+
+	// The Role without a namespace is in the default namespace
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, rbacConfig(), "RoleBinding/rbac.authorization.k8s.io/v1/default/worker", "rolebinding-role-exists").Grade)
+
+	res = testObjectCheck(t, rbacConfig(), "ClusterRoleBinding/rbac.authorization.k8s.io/v1//wrong-kind", "rolebinding-role-exists")
+	assert.Equal(t, scorecard.GradeCritical, res.Grade)
+	assert.Equal(t, "The ClusterRoleBinding refers to a Role", res.Comments[0].Summary)
+}
+
+func TestRoleBindingSubjectsExist(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, rbacConfig(), "RoleBinding/rbac.authorization.k8s.io/v1/apps/missing-role", "rolebinding-subjects-exist").Grade)
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, rbacConfig(), "ClusterRoleBinding/rbac.authorization.k8s.io/v1//wrong-kind", "rolebinding-subjects-exist").Grade)
+
+	res := testObjectCheck(t, rbacConfig(), "RoleBinding/rbac.authorization.k8s.io/v1/apps/reader", "rolebinding-subjects-exist")
+	assert.Equal(t, scorecard.GradeCritical, res.Grade)
+	assert.Len(t, res.Comments, 1)
+	assert.Equal(t, "The ServiceAccount missing does not exist", res.Comments[0].Summary)
+	assert.Equal(t, "subjects[1].name", res.Comments[0].FieldPath)
+
+	// The ServiceAccount without a namespace is in the default namespace
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, rbacConfig(), "RoleBinding/rbac.authorization.k8s.io/v1/default/worker", "rolebinding-subjects-exist").Grade)
+}
+
+func TestRoleBindingChecksNotEnabled(t *testing.T) {
+	t.Parallel()
+	for _, check := range []string{"RoleBinding Role Exists", "RoleBinding Subjects Exist"} {
+		assert.True(t, wasSkipped(t, config.Configuration{
+			AllFiles: []ks.NamedReader{testFile("rbac.yaml")},
+		}, check), check)
+	}
+}
@@ -0,0 +1,73 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+func TestReplicaSetContainerChecks(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "replicaset.yaml", "Container Image Tag", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "spec.template.spec.containers[0].image", comments[0].FieldPath)
+}
+
+func TestReplicaSetTargetedByService(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "replicaset.yaml", "Service Targets Pod", scorecard.GradeAllOK)
+}
+
+func TestReplicaSetSelectorLabelsMatching(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "replicaset.yaml", "Workload Pod Selector labels match template metadata labels", scorecard.GradeAllOK)
+}
+
+func TestReplicaSetHasNoPodDisruptionBudget(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "replicaset.yaml", "Workload has PodDisruptionBudget", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "No matching PodDisruptionBudget was found", comments[0].Summary)
+}
+
+func TestReplicaSetTargetedByHPAHasReplicas(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("replicaset-hpa.yaml")},
+		KubernetesVersion: config.Semver{Major: 1, Minor: 23},
+	}, "Workload targeted by HPA does not have replicas configured", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The ReplicaSet is targeted by a HPA, but a static replica count is configured", comments[0].Summary)
+}
+
+func TestReplicationControllerContainerChecks(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "replicationcontroller.yaml", "Container Image Tag", scorecard.GradeCritical)
+}
+
+func TestReplicationControllerTargetedByService(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "replicationcontroller.yaml", "Service Targets Pod", scorecard.GradeAllOK)
+}
+
+func TestReplicationControllerSelectorLabelsMatching(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "replicationcontroller.yaml", "Workload Pod Selector labels match template metadata labels", scorecard.GradeAllOK)
+}
+
+func TestReplicationControllerHasNoPodDisruptionBudget(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "replicationcontroller.yaml", "Workload has PodDisruptionBudget", scorecard.GradeCritical)
+}
+
+func TestPodTemplateContainerChecks(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "podtemplate.yaml", "Container Image Tag", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "template.spec.containers[0].image", comments[0].FieldPath)
+	assert.Equal(t, 12, comments[0].FileLocation.Line)
+}
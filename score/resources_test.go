@@ -0,0 +1,76 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+func TestContainerResourceLimitsAboveRequests(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-resource-units.yaml", "Container Resource Limits Above Requests", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "milli", comments[0].Path)
+	assert.Equal(t, "The cpu limit 200m is lower than the request 250m", comments[0].Summary)
+	assert.Equal(t, "spec.template.spec.containers[1].resources.limits.cpu", comments[0].FieldPath)
+}
+
+func TestContainerResourceUnits(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-resource-units.yaml", "Container Resource Units", scorecard.GradeWarning)
+	assert.Equal(t, []string{
+		"The memory requests 512 is 512 bytes",
+		"The CPU limits 1k is 1000 cores",
+		"The memory limits 1G uses the decimal unit G",
+		"The memory requests 500m is a fraction of a byte",
+	}, summariesOf(comments))
+	assert.Equal(t, "spec.template.spec.containers[0].resources.limits.cpu", comments[1].FieldPath)
+	assert.Equal(t, "CPU quantities without a unit are in cores. Use the m suffix for millicores, such as 1000m.", comments[1].Description)
+}
+
+func TestContainerResourceUnitsAllGood(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-resource-bounds.yaml", "Container Resource Units", scorecard.GradeAllOK)
+}
+
+func TestContainerResourceBoundsNotConfigured(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-resource-bounds.yaml", "Container Resource Bounds", scorecard.GradeAllOK)
+}
+
+func TestContainerResourceBounds(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles: []ks.NamedReader{testFile("pod-resource-bounds.yaml")},
+		ResourceBounds: []config.ResourceBoundsRule{
+			{Namespaces: []string{"batch"}, CPURequest: config.QuantityRange{Max: "32"}},
+			{
+				CPURequest:           config.QuantityRange{Min: "50m", Max: "8"},
+				MemoryRequest:        config.QuantityRange{Min: "128Mi", Max: "4Gi"},
+				MaxLimitRequestRatio: 4,
+			},
+		},
+	}, "Container Resource Bounds", scorecard.GradeCritical)
+	assert.Equal(t, []string{
+		"The CPU request 10m is lower than the minimum 50m",
+		"The CPU limit is 10.0 times the request",
+		"The memory request 64Mi is lower than the minimum 128Mi",
+		"The CPU request 16 is higher than the maximum 8",
+	}, summariesOf(comments))
+	assert.Equal(t, "spec.template.spec.containers[0].resources.limits.cpu", comments[1].FieldPath)
+}
+
+func TestContainerResourceBoundsScopedByLabel(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles: []ks.NamedReader{testFile("pod-resource-bounds.yaml")},
+		ResourceBounds: []config.ResourceBoundsRule{
+			{Labels: map[string]string{"tier": "batch"}, CPURequest: config.QuantityRange{Max: "32"}},
+			{CPURequest: config.QuantityRange{Min: "50m", Max: "8"}},
+		},
+	}, "Container Resource Bounds", scorecard.GradeAllOK)
+}
@@ -0,0 +1,185 @@
+package schema
+
+import (
+	"fmt"
+
+	"github.com/younes-bami/kube-score/config"
+	"github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/score/checks"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+func Register(kubernetesVersion config.Semver, allChecks *checks.Checks) {
+	allChecks.RegisterOptionalMetaCheck("Strict Decoding", `Validates objects of built-in kinds against the Kubernetes v1.27 types of kube-score, and reports fields that are unknown, set more than once, or have the wrong type. Also makes sure that the apiVersion is served by the --kubernetes-version, according to a list of the APIs that have been added and removed since v1.16. This is not a validation against the OpenAPI schema of the --kubernetes-version, and custom resources are not validated.`, strictDecoding(kubernetesVersion))
+}
+
+// servedVersions is the range of Kubernetes versions that an apiVersion and kind is served by
+type servedVersions struct {
+	// since is the first version that serves the API, or zero if it has been served since before v1.16
+	since config.Semver
+
+	// removed is the first version that no longer serves the API, or zero if it is still served
+	removed config.Semver
+}
+
+// typesVersion is the Kubernetes version of the types that objects are decoded with
+var typesVersion = config.Semver{Major: 1, Minor: 27}
+
+var (
+	v1_16 = config.Semver{Major: 1, Minor: 16}
+	v1_19 = config.Semver{Major: 1, Minor: 19}
+	v1_21 = config.Semver{Major: 1, Minor: 21}
+	v1_22 = config.Semver{Major: 1, Minor: 22}
+	v1_23 = config.Semver{Major: 1, Minor: 23}
+	v1_25 = config.Semver{Major: 1, Minor: 25}
+	v1_26 = config.Semver{Major: 1, Minor: 26}
+	v1_27 = config.Semver{Major: 1, Minor: 27}
+	v1_29 = config.Semver{Major: 1, Minor: 29}
+)
+
+// served contains the APIs that have been added or removed since Kubernetes v1.16, keyed by apiVersion and kind.
+// APIs that are not listed are assumed to be served by all versions.
+var served = map[string]map[string]servedVersions{
+	"extensions/v1beta1": {
+		"DaemonSet":         {removed: v1_16},
+		"Deployment":        {removed: v1_16},
+		"ReplicaSet":        {removed: v1_16},
+		"NetworkPolicy":     {removed: v1_16},
+		"PodSecurityPolicy": {removed: v1_16},
+		"Ingress":           {removed: v1_22},
+	},
+	"apps/v1beta1": {
+		"Deployment":         {removed: v1_16},
+		"StatefulSet":        {removed: v1_16},
+		"ControllerRevision": {removed: v1_16},
+	},
+	"apps/v1beta2": {
+		"DaemonSet":          {removed: v1_16},
+		"Deployment":         {removed: v1_16},
+		"ReplicaSet":         {removed: v1_16},
+		"StatefulSet":        {removed: v1_16},
+		"ControllerRevision": {removed: v1_16},
+	},
+	"networking.k8s.io/v1beta1": {
+		"Ingress":      {removed: v1_22},
+		"IngressClass": {removed: v1_22},
+	},
+	"networking.k8s.io/v1": {
+		"Ingress":      {since: v1_19},
+		"IngressClass": {since: v1_19},
+	},
+	"rbac.authorization.k8s.io/v1beta1": {
+		"Role":               {removed: v1_22},
+		"RoleBinding":        {removed: v1_22},
+		"ClusterRole":        {removed: v1_22},
+		"ClusterRoleBinding": {removed: v1_22},
+	},
+	"admissionregistration.k8s.io/v1beta1": {
+		"MutatingWebhookConfiguration":   {removed: v1_22},
+		"ValidatingWebhookConfiguration": {removed: v1_22},
+	},
+	"apiextensions.k8s.io/v1beta1": {
+		"CustomResourceDefinition": {removed: v1_22},
+	},
+	"certificates.k8s.io/v1beta1": {
+		"CertificateSigningRequest": {removed: v1_22},
+	},
+	"coordination.k8s.io/v1beta1": {
+		"Lease": {removed: v1_22},
+	},
+	"scheduling.k8s.io/v1beta1": {
+		"PriorityClass": {removed: v1_22},
+	},
+	"storage.k8s.io/v1beta1": {
+		"CSIDriver":          {removed: v1_22},
+		"CSINode":            {removed: v1_22},
+		"StorageClass":       {removed: v1_22},
+		"VolumeAttachment":   {removed: v1_22},
+		"CSIStorageCapacity": {removed: v1_27},
+	},
+	"batch/v1beta1": {
+		"CronJob": {removed: v1_25},
+	},
+	"batch/v1": {
+		"CronJob": {since: v1_21},
+	},
+	"policy/v1beta1": {
+		"PodDisruptionBudget": {removed: v1_25},
+		"PodSecurityPolicy":   {removed: v1_25},
+	},
+	"policy/v1": {
+		"PodDisruptionBudget": {since: v1_21},
+	},
+	"discovery.k8s.io/v1beta1": {
+		"EndpointSlice": {removed: v1_25},
+	},
+	"discovery.k8s.io/v1": {
+		"EndpointSlice": {since: v1_21},
+	},
+	"events.k8s.io/v1beta1": {
+		"Event": {removed: v1_25},
+	},
+	"node.k8s.io/v1beta1": {
+		"RuntimeClass": {removed: v1_25},
+	},
+	"autoscaling/v2beta1": {
+		"HorizontalPodAutoscaler": {removed: v1_25},
+	},
+	"autoscaling/v2beta2": {
+		"HorizontalPodAutoscaler": {removed: v1_26},
+	},
+	"autoscaling/v2": {
+		"HorizontalPodAutoscaler": {since: v1_23},
+	},
+	"flowcontrol.apiserver.k8s.io/v1beta1": {
+		"FlowSchema":                 {removed: v1_26},
+		"PriorityLevelConfiguration": {removed: v1_26},
+	},
+	"flowcontrol.apiserver.k8s.io/v1beta2": {
+		"FlowSchema":                 {removed: v1_29},
+		"PriorityLevelConfiguration": {removed: v1_29},
+	},
+}
+
+func strictDecoding(kubernetesVersion config.Semver) func(meta domain.BothMeta) (scorecard.TestScore, error) {
+	return func(meta domain.BothMeta) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+
+		if versions, ok := served[meta.TypeMeta.APIVersion][meta.TypeMeta.Kind]; ok {
+			if kubernetesVersion.LessThan(versions.since) {
+				score.Grade = scorecard.GradeCritical
+				score.AddCommentWithFieldPath("", "apiVersion",
+					fmt.Sprintf("%s/%s is not served by Kubernetes %s", meta.TypeMeta.APIVersion, meta.TypeMeta.Kind, kubernetesVersion),
+					fmt.Sprintf("The API was added in Kubernetes %s. Use an older apiVersion, or set --kubernetes-version to the version that you are running.", versions.since),
+				)
+			}
+			if versions.removed != (config.Semver{}) && !kubernetesVersion.LessThan(versions.removed) {
+				score.Grade = scorecard.GradeCritical
+				score.AddCommentWithFieldPath("", "apiVersion",
+					fmt.Sprintf("%s/%s is not served by Kubernetes %s", meta.TypeMeta.APIVersion, meta.TypeMeta.Kind, kubernetesVersion),
+					fmt.Sprintf("The API was removed in Kubernetes %s, and the object will be rejected. Migrate to a newer apiVersion.", versions.removed),
+				)
+			}
+		}
+
+		for _, schemaErr := range meta.SchemaErrors {
+			var description string
+			switch schemaErr.Type {
+			case domain.SchemaErrorUnknownField:
+				description = fmt.Sprintf("The field is not part of the schema of %s/%s, and is ignored by Kubernetes. This is often caused by a typo, or by wrong indentation.", meta.TypeMeta.APIVersion, meta.TypeMeta.Kind)
+				if typesVersion.LessThan(kubernetesVersion) {
+					description += fmt.Sprintf(" kube-score knows the fields of Kubernetes %s, and the field may have been added in a later version.", typesVersion)
+				}
+			case domain.SchemaErrorDuplicateField:
+				description = "The field is set more than once, and only one of the values is used by Kubernetes."
+			case domain.SchemaErrorWrongType:
+				description = "The value does not match the type of the field, and the object will be rejected by Kubernetes. The field was ignored when scoring the object."
+			}
+
+			score.Grade = scorecard.GradeCritical
+			score.AddCommentWithFieldPath(schemaErr.FieldPath, schemaErr.FieldPath, schemaErr.Message, description)
+		}
+
+		return
+	}
+}
@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+func TestStrictDecodingServedVersions(t *testing.T) {
+	cases := []struct {
+		apiVersion, kind string
+		version          config.Semver
+		expected         scorecard.Grade
+	}{
+		{"batch/v1beta1", "CronJob", config.Semver{Major: 1, Minor: 24}, scorecard.GradeAllOK},
+		{"batch/v1beta1", "CronJob", config.Semver{Major: 1, Minor: 25}, scorecard.GradeCritical},
+		{"batch/v1", "CronJob", config.Semver{Major: 1, Minor: 20}, scorecard.GradeCritical},
+		{"batch/v1", "CronJob", config.Semver{Major: 1, Minor: 21}, scorecard.GradeAllOK},
+		{"autoscaling/v2", "HorizontalPodAutoscaler", config.Semver{Major: 1, Minor: 22}, scorecard.GradeCritical},
+		{"extensions/v1beta1", "Deployment", config.Semver{Major: 1, Minor: 15}, scorecard.GradeAllOK},
+		{"extensions/v1beta1", "Deployment", config.Semver{Major: 1, Minor: 16}, scorecard.GradeCritical},
+		{"apps/v1", "Deployment", config.Semver{Major: 1, Minor: 27}, scorecard.GradeAllOK},
+		{"example.com/v1", "Custom", config.Semver{Major: 1, Minor: 27}, scorecard.GradeAllOK},
+	}
+
+	for _, tc := range cases {
+		score, err := strictDecoding(tc.version)(ks.BothMeta{TypeMeta: v1.TypeMeta{APIVersion: tc.apiVersion, Kind: tc.kind}})
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, score.Grade, "%s/%s in %s", tc.apiVersion, tc.kind, tc.version)
+	}
+}
+
+func TestStrictDecodingErrors(t *testing.T) {
+	score, err := strictDecoding(config.Semver{Major: 1, Minor: 27})(ks.BothMeta{
+		TypeMeta: v1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		SchemaErrors: []ks.SchemaError{
+			{Type: ks.SchemaErrorUnknownField, FieldPath: "spec.template.spec.containers[0].resouces", Message: "Unknown field spec.template.spec.containers[0].resouces"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, scorecard.GradeCritical, score.Grade)
+	assert.Len(t, score.Comments, 1)
+	assert.Equal(t, "spec.template.spec.containers[0].resouces", score.Comments[0].FieldPath)
+	assert.Equal(t, "Unknown field spec.template.spec.containers[0].resouces", score.Comments[0].Summary)
+}
+
+func TestStrictDecodingErrorsNewerVersion(t *testing.T) {
+	score, err := strictDecoding(config.Semver{Major: 1, Minor: 29})(ks.BothMeta{
+		TypeMeta: v1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		SchemaErrors: []ks.SchemaError{
+			{Type: ks.SchemaErrorUnknownField, FieldPath: "spec.initContainers[0].restartPolicy", Message: "Unknown field spec.initContainers[0].restartPolicy"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, score.Comments, 1)
+	assert.Contains(t, score.Comments[0].Description, "kube-score knows the fields of Kubernetes v1.27")
+}
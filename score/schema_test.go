@@ -0,0 +1,58 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+func TestStrictDecodingUnknownField(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("strict-decoding.yaml")},
+		KubernetesVersion:    config.Semver{Major: 1, Minor: 27},
+		EnabledOptionalTests: map[string]struct{}{"strict-decoding": {}},
+	}, "Strict Decoding", scorecard.GradeCritical)
+
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "Unknown field spec.template.spec.containers[0].resouces", comments[0].Summary)
+	assert.Equal(t, 14, comments[0].FileLocation.Line)
+	assert.Equal(t, 11, comments[0].FileLocation.Column)
+}
+
+func TestStrictDecodingValid(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("deployment-host-antiaffinity-1-replica.yaml")},
+		KubernetesVersion:    config.Semver{Major: 1, Minor: 27},
+		EnabledOptionalTests: map[string]struct{}{"strict-decoding": {}},
+	}, "Strict Decoding", scorecard.GradeAllOK)
+}
+
+func TestStrictDecodingEnabledWithAnnotation(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:                    []ks.NamedReader{testFile("strict-decoding-annotation.yaml")},
+		KubernetesVersion:           config.Semver{Major: 1, Minor: 27},
+		UseOptionalChecksAnnotation: true,
+	}, "Strict Decoding", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+}
+
+func TestStrictDecodingWrongType(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("strict-decoding-wrong-type.yaml")},
+		KubernetesVersion:    config.Semver{Major: 1, Minor: 27},
+		EnabledOptionalTests: map[string]struct{}{"strict-decoding": {}},
+		StrictDecoding:       true,
+	}, "Strict Decoding", scorecard.GradeCritical)
+
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "Field spec.replicas has the wrong type, expected an integer", comments[0].Summary)
+	assert.Equal(t, 6, comments[0].FileLocation.Line)
+}
@@ -1,6 +1,8 @@
 package score
 
 import (
+	"fmt"
+
 	"github.com/younes-bami/kube-score/config"
 	ks "github.com/younes-bami/kube-score/domain"
 	"github.com/younes-bami/kube-score/score/apps"
@@ -8,14 +10,20 @@ import (
 	"github.com/younes-bami/kube-score/score/container"
 	"github.com/younes-bami/kube-score/score/cronjob"
 	"github.com/younes-bami/kube-score/score/disruptionbudget"
+	"github.com/younes-bami/kube-score/score/gateway"
 	"github.com/younes-bami/kube-score/score/hpa"
 	"github.com/younes-bami/kube-score/score/ingress"
 	"github.com/younes-bami/kube-score/score/meta"
 	"github.com/younes-bami/kube-score/score/networkpolicy"
+	"github.com/younes-bami/kube-score/score/podsecurity"
 	"github.com/younes-bami/kube-score/score/podtopologyspreadconstraints"
 	"github.com/younes-bami/kube-score/score/probes"
+	"github.com/younes-bami/kube-score/score/rbac"
+	"github.com/younes-bami/kube-score/score/schema"
+	"github.com/younes-bami/kube-score/score/secrets"
 	"github.com/younes-bami/kube-score/score/security"
 	"github.com/younes-bami/kube-score/score/service"
+	"github.com/younes-bami/kube-score/score/serviceaccount"
 	"github.com/younes-bami/kube-score/score/stable"
 	"github.com/younes-bami/kube-score/scorecard"
 	corev1 "k8s.io/api/core/v1"
@@ -26,18 +34,25 @@ func RegisterAllChecks(allObjects ks.AllTypes, cnf config.Configuration) *checks
 	allChecks := checks.New(cnf)
 
 	ingress.Register(allChecks, allObjects)
+	gateway.Register(allChecks, allObjects, allObjects, allObjects, allObjects)
 	cronjob.Register(allChecks)
 	container.Register(allChecks, cnf)
 	disruptionbudget.Register(allChecks, allObjects)
 	networkpolicy.Register(allChecks, allObjects, allObjects, allObjects)
 	probes.Register(allChecks, allObjects)
-	security.Register(allChecks)
+	security.Register(allChecks, cnf)
+	podsecurity.Register(allChecks, cnf)
 	service.Register(allChecks, allObjects, allObjects)
+	serviceaccount.Register(allChecks, allObjects)
+	rbac.Register(allChecks, allObjects, allObjects)
+	secrets.Register(allChecks)
 	stable.Register(cnf.KubernetesVersion, allChecks)
+	schema.Register(cnf.KubernetesVersion, allChecks)
 	apps.Register(allChecks, allObjects.HorizontalPodAutoscalers(), allObjects.Services())
 	meta.Register(allChecks)
 	hpa.Register(allChecks, allObjects.Metas())
 	podtopologyspreadconstraints.Register(allChecks)
+	allChecks.RegisterPseudoCheck(parseErrorCheck)
 
 	return allChecks
 }
@@ -64,6 +79,22 @@ func (p *podSpeccer) FileLocation() ks.FileLocation {
 	return ks.FileLocation{}
 }
 
+// podTemplatePath returns the field path of the pod template in an object that has one.
+// The field paths of comments from pod checks are relative to the pod template.
+func podTemplatePath(ps ks.PodSpecer) string {
+	if pather, ok := ps.(ks.PodTemplatePather); ok {
+		return pather.PodTemplatePath()
+	}
+	if ps.GetTypeMeta().Kind == "CronJob" {
+		return "spec.jobTemplate.spec.template"
+	}
+	return "spec.template"
+}
+
+// parseErrorCheck is the pseudo-check that documents that could not be parsed are reported as,
+// when parse errors are tolerated
+var parseErrorCheck = checks.NewCheck("Parse Error", "all", "Reports documents that could not be parsed", false)
+
 // Score runs a pre-configured list of tests against the files defined in the configuration, and returns a scorecard.
 // Additional configuration and tuning parameters can be provided via the config.
 func Score(allObjects ks.AllTypes, cnf config.Configuration) (*scorecard.Scorecard, error) {
@@ -118,11 +149,24 @@ func Score(allObjects ks.AllTypes, cnf config.Configuration) (*scorecard.Scoreca
 		o := newObject(podspecer.GetTypeMeta(), podspecer.GetObjectMeta())
 		for _, test := range allChecks.Pods() {
 			score, _ := test.Fn(podspecer)
+			score.PrefixFieldPaths(podTemplatePath(podspecer))
 			o.Add(score, test.Check, podspecer,
 				podspecer.GetObjectMeta().Annotations,
 				podspecer.GetPodTemplateSpec().Annotations,
 			)
 		}
+
+		workload, ok := podspecer.(ks.Workload)
+		if !ok {
+			continue
+		}
+		for _, test := range allChecks.Workloads() {
+			fn, err := test.Fn(workload)
+			if err != nil {
+				return nil, err
+			}
+			o.Add(fn, test.Check, workload, workload.GetObjectMeta().Annotations)
+		}
 	}
 
 	for _, service := range allObjects.Services() {
@@ -191,6 +235,50 @@ func Score(allObjects ks.AllTypes, cnf config.Configuration) (*scorecard.Scoreca
 		}
 	}
 
+	for _, route := range allObjects.Routes() {
+		o := newObject(route.GetTypeMeta(), route.GetObjectMeta())
+		for _, test := range allChecks.Routes() {
+			fn, err := test.Fn(route)
+			if err != nil {
+				return nil, err
+			}
+			o.Add(fn, test.Check, route, route.GetObjectMeta().Annotations)
+		}
+	}
+
+	for _, role := range allObjects.Roles() {
+		o := newObject(role.GetTypeMeta(), role.GetObjectMeta())
+		for _, test := range allChecks.Roles() {
+			fn, err := test.Fn(role)
+			if err != nil {
+				return nil, err
+			}
+			o.Add(fn, test.Check, role, role.GetObjectMeta().Annotations)
+		}
+	}
+
+	for _, binding := range allObjects.RoleBindings() {
+		o := newObject(binding.GetTypeMeta(), binding.GetObjectMeta())
+		for _, test := range allChecks.RoleBindings() {
+			fn, err := test.Fn(binding)
+			if err != nil {
+				return nil, err
+			}
+			o.Add(fn, test.Check, binding, binding.GetObjectMeta().Annotations)
+		}
+	}
+
+	for _, configMap := range allObjects.ConfigMaps() {
+		o := newObject(configMap.ConfigMap().TypeMeta, configMap.ConfigMap().ObjectMeta)
+		for _, test := range allChecks.ConfigMaps() {
+			fn, err := test.Fn(configMap)
+			if err != nil {
+				return nil, err
+			}
+			o.Add(fn, test.Check, configMap, configMap.ConfigMap().Annotations)
+		}
+	}
+
 	for _, pdb := range allObjects.PodDisruptionBudgets() {
 		o := newObject(pdb.GetTypeMeta(), pdb.GetObjectMeta())
 		for _, test := range allChecks.PodDisruptionBudgets() {
@@ -202,5 +290,18 @@ func Score(allObjects ks.AllTypes, cnf config.Configuration) (*scorecard.Scoreca
 		}
 	}
 
+	if _, ignored := cnf.IgnoredTests[parseErrorCheck.ID]; !ignored {
+		for _, parseErr := range allObjects.ParseErrors() {
+			objectMeta := parseErr.ObjectMeta
+			if objectMeta.Name == "" {
+				objectMeta.Name = fmt.Sprintf("%s:%d", parseErr.Location.Name, parseErr.Location.Line)
+			}
+			o := newObject(parseErr.TypeMeta, objectMeta)
+			score := scorecard.TestScore{Grade: scorecard.GradeCritical}
+			score.AddComment("", fmt.Sprintf("Failed to parse document: %v", parseErr.Err), "The document is not valid YAML, or does not match the schema of its kind. Fix the document to have it scored.")
+			o.Add(score, parseErrorCheck, parseErr)
+		}
+	}
+
 	return &scoreCard, nil
 }
@@ -73,6 +73,22 @@ func testScore(config config.Configuration) (scorecard.Scorecard, error) {
 	return *card, err
 }
 
+// testObjectCheck runs all tests, and returns the result of the check with the ID checkID on the object with the key
+// object, such as "Deployment/apps/v1/default/app". The test fails if the check was not run on the object.
+// Files can only be read once, so a new configuration must be created for each call.
+func testObjectCheck(t *testing.T, config config.Configuration, object, checkID string) scorecard.TestScore {
+	sc, err := testScore(config)
+	assert.NoError(t, err)
+
+	for _, c := range sc[object].Checks {
+		if c.Check.ID == checkID {
+			return c
+		}
+	}
+	t.Fatalf("%s was not tested on %s", checkID, object)
+	return scorecard.TestScore{}
+}
+
 func testExpectedScore(t *testing.T, filename string, testcase string, expectedScore scorecard.Grade) []scorecard.TestScoreComment {
 	return testExpectedScoreWithConfig(t, config.Configuration{
 		AllFiles:          []ks.NamedReader{testFile(filename)},
@@ -264,6 +280,48 @@ func TestPodContainerPullPolicyAlways(t *testing.T) {
 	testExpectedScore(t, "pod-image-pullpolicy-always.yaml", "Container Image Pull Policy", scorecard.GradeAllOK)
 }
 
+func TestPodContainerTagRegistryPort(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-image-registry-port.yaml", "Container Image Tag", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "port", comments[0].Path)
+}
+
+func TestPodContainerImagePolicyDefault(t *testing.T) {
+	t.Parallel()
+	// Images with both a tag and a digest, as written by tools that pin images, are accepted
+	comments := testExpectedScore(t, "pod-image-policy.yaml", "Container Image Policy", scorecard.GradeAllOK)
+	assert.Empty(t, comments)
+}
+
+func TestPodContainerImagePolicy(t *testing.T) {
+	t.Parallel()
+	forbiddenTags, err := config.ParseImageTagPatterns([]string{"latest", ".*-SNAPSHOT"})
+	assert.NoError(t, err)
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:               []ks.NamedReader{testFile("pod-image-policy.yaml")},
+		AllowedImageRegistries: []string{"registry.example.com:5000/team"},
+		RequireImageDigest:     true,
+		ForbiddenImageTags:     forbiddenTags,
+	}, "Container Image Policy", scorecard.GradeCritical)
+	assert.Equal(t, []string{
+		"The image is pulled from docker.io, which is not allowed",
+		"The image is not pinned by digest",
+		"The image is not pinned by digest",
+		"The image tag 1.0-SNAPSHOT is not allowed",
+		"The image is pulled from registry.example.com:5000, which is not allowed",
+	}, summariesOf(comments))
+	assert.Equal(t, "The tag matches the forbidden pattern .*-SNAPSHOT. Use a release tag instead.", comments[3].Description)
+}
+
+func TestPodContainerImagePolicyInvalid(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-image-registry-port.yaml", "Container Image Policy", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "invalid", comments[0].Path)
+	assert.Equal(t, "The image reference is invalid", comments[0].Summary)
+}
+
 func TestConfigMapMultiDash(t *testing.T) {
 	t.Parallel()
 	_, err := testScore(config.Configuration{
@@ -502,14 +560,18 @@ func TestPodEnvDuplicated(t *testing.T) {
 
 	expected := []scorecard.TestScoreComment{
 		{
-			Path:        "foobar",
-			Summary:     "Environment Variable Key Duplication",
-			Description: "Container environment variable key 'bar' is duplicated",
+			Path:         "foobar",
+			Summary:      "Environment Variable Key Duplication",
+			Description:  "Container environment variable key 'bar' is duplicated",
+			FieldPath:    "spec.containers[0].env[2]",
+			FileLocation: ks.FileLocation{Name: "testdata/pod-env-duplicated.yaml", Line: 14, Column: 7},
 		},
 		{
-			Path:        "foobar",
-			Summary:     "Environment Variable Key Duplication",
-			Description: "Container environment variable key 'baz' is duplicated",
+			Path:         "foobar",
+			Summary:      "Environment Variable Key Duplication",
+			Description:  "Container environment variable key 'baz' is duplicated",
+			FieldPath:    "spec.containers[0].env[4]",
+			FileLocation: ks.FileLocation{Name: "testdata/pod-env-duplicated.yaml", Line: 18, Column: 7},
 		},
 	}
 	diff := cmp.Diff(expected, actual)
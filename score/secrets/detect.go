@@ -0,0 +1,131 @@
+package secrets
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Finding is a value that looks like a hard-coded credential
+type Finding struct {
+	// Kind describes what the value looks like, such as "an AWS access key ID"
+	Kind string
+
+	// Redacted is a shortened version of the matched value, that does not reveal the credential
+	Redacted string
+
+	// Certain is true if the value matches a known credential format, and false if it only has high entropy
+	Certain bool
+}
+
+var patterns = []struct {
+	kind string
+	re   *regexp.Regexp
+
+	// group is the submatch that contains the credential, or 0 for the whole match
+	group int
+}{
+	{"a private key", regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )*PRIVATE KEY(?: BLOCK)?-----`), 0},
+	{"an AWS access key ID", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`), 0},
+	{"a GitHub token", regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`), 0},
+	{"a Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`), 0},
+	{"a Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`), 0},
+	{"a JSON Web Token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]{10,}`), 0},
+	{"a password in a connection URL", regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@]+:([^/\s@]+)@`), 1},
+}
+
+// placeholder matches values that refer to another variable or a template, instead of containing a credential
+var placeholder = regexp.MustCompile(`^(?:\$\(.+\)|\$\{.+\}|\$[A-Za-z_][A-Za-z0-9_]*|\{\{.+\}\}|<.+>|\*+|x+|X+)$`)
+
+// publicPEMBlock matches certificates and public keys, that are not secret but have random looking base64 bodies
+var publicPEMBlock = regexp.MustCompile(`(?s)-----BEGIN (?:[A-Z0-9]+ )*(?:CERTIFICATE(?: REQUEST)?|PUBLIC KEY)-----.*?-----END [A-Z0-9 ]+-----`)
+
+// token separates a value into candidates for the entropy check
+var token = regexp.MustCompile(`[A-Za-z0-9+/_-]{20,}={0,2}`)
+
+// Detect returns all credentials that are found in the value. Values that match a known credential format are
+// returned first, and random looking tokens are only returned if no known format matched. The bodies of certificates
+// and public keys are not checked for random looking tokens.
+func Detect(value string) []Finding {
+	var res []Finding
+	for _, p := range patterns {
+		for _, match := range p.re.FindAllStringSubmatch(value, -1) {
+			secret := match[p.group]
+			if placeholder.MatchString(secret) {
+				continue
+			}
+			res = append(res, Finding{Kind: p.kind, Redacted: Redact(match[0]), Certain: true})
+		}
+	}
+	if len(res) > 0 {
+		return res
+	}
+
+	value = publicPEMBlock.ReplaceAllString(value, "")
+	for _, candidate := range token.FindAllString(value, -1) {
+		if isRandom(candidate) {
+			res = append(res, Finding{Kind: "a high-entropy string", Redacted: Redact(candidate)})
+		}
+	}
+	return res
+}
+
+// isRandom returns true if the token looks like a randomly generated key.
+// Tokens must have upper and lower case letters and digits, which excludes names, hex encoded hashes and paths,
+// and must have an entropy close to the highest possible entropy for a token of its length.
+func isRandom(token string) bool {
+	var upper, lower bool
+	digits := 0
+	for _, r := range token {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= '0' && r <= '9':
+			digits++
+		}
+	}
+	if !upper || !lower || digits < 2 {
+		return false
+	}
+	maxEntropy := math.Log2(math.Min(float64(len(token)), 64))
+	return entropy(token) >= 0.85*maxEntropy
+}
+
+// entropy returns the Shannon entropy of s, in bits per character
+func entropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	var res float64
+	n := float64(len([]rune(s)))
+	for _, count := range counts {
+		p := float64(count) / n
+		res -= p * math.Log2(p)
+	}
+	return res
+}
+
+// Redact returns a version of the value that is safe to print. Only the first characters of long values are kept,
+// which is enough to recognize the credential without revealing it.
+func Redact(value string) string {
+	const keep = 4
+	if strings.HasPrefix(value, "-----BEGIN") {
+		return value
+	}
+	if i := strings.Index(value, "://"); i >= 0 {
+		// Keep the scheme and the user of connection URLs
+		if at := strings.LastIndex(value, "@"); at > i {
+			userinfo := value[i+3 : at]
+			if colon := strings.Index(userinfo, ":"); colon >= 0 {
+				return value[:i+3] + userinfo[:colon] + ":****@"
+			}
+		}
+	}
+	if len(value) < 4*keep {
+		return "****"
+	}
+	return value[:keep] + "****"
+}
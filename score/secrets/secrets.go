@@ -0,0 +1,85 @@
+package secrets
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/score/checks"
+	"github.com/younes-bami/kube-score/score/internal"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+func Register(allChecks *checks.Checks) {
+	allChecks.RegisterPodCheck("Container Hard-coded Secrets", `Makes sure that environment variables, commands and args of containers do not contain credentials, such as keys, tokens or passwords. Matched values are redacted.`, containerHardcodedSecrets)
+	allChecks.RegisterConfigMapCheck("ConfigMap Hard-coded Secrets", `Makes sure that the data of ConfigMaps does not contain credentials, such as keys, tokens or passwords. Matched values are redacted.`, configMapHardcodedSecrets)
+}
+
+// addFindings adds a comment for each finding, and lowers the grade. Findings of a known format are critical,
+// and high-entropy strings are warnings as they can be false positives.
+func addFindings(score *scorecard.TestScore, findings []Finding, path, fieldPath, where, description string) {
+	for _, f := range findings {
+		grade := scorecard.GradeWarning
+		if f.Certain {
+			grade = scorecard.GradeCritical
+		}
+		if grade < score.Grade {
+			score.Grade = grade
+		}
+		score.AddCommentWithFieldPath(path, fieldPath, fmt.Sprintf("%s contains %s (%s)", where, f.Kind, f.Redacted), description)
+	}
+}
+
+// containerHardcodedSecrets checks the environment variables, commands and args of all containers for credentials
+func containerHardcodedSecrets(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+	pod := ps.GetPodTemplateSpec().Spec
+	allContainers := pod.InitContainers
+	allContainers = append(allContainers, pod.Containers...)
+
+	score.Grade = scorecard.GradeAllOK
+
+	for i, container := range allContainers {
+		containerPath := internal.ContainerFieldPath(pod, i)
+
+		for j, env := range container.Env {
+			addFindings(&score, Detect(env.Value), env.Name, fmt.Sprintf("%s.env[%d].value", containerPath, j),
+				fmt.Sprintf("The environment variable %s of container %s", env.Name, container.Name),
+				"Credentials in manifests are visible to everyone with access to the repository or the cluster. Store the value in a Secret, and refer to it with valueFrom.secretKeyRef.")
+		}
+
+		for j, arg := range container.Command {
+			addFindings(&score, Detect(arg), container.Name, fmt.Sprintf("%s.command[%d]", containerPath, j),
+				fmt.Sprintf("The command of container %s", container.Name),
+				"Credentials in manifests are visible to everyone with access to the repository or the cluster. Store the value in a Secret, and refer to it as an environment variable with $(NAME).")
+		}
+
+		for j, arg := range container.Args {
+			addFindings(&score, Detect(arg), container.Name, fmt.Sprintf("%s.args[%d]", containerPath, j),
+				fmt.Sprintf("The args of container %s", container.Name),
+				"Credentials in manifests are visible to everyone with access to the repository or the cluster. Store the value in a Secret, and refer to it as an environment variable with $(NAME).")
+		}
+	}
+
+	return
+}
+
+// configMapHardcodedSecrets checks all values of the ConfigMap for credentials
+func configMapHardcodedSecrets(cm ks.ConfigMap) (score scorecard.TestScore, err error) {
+	data := cm.ConfigMap().Data
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	score.Grade = scorecard.GradeAllOK
+	for _, key := range keys {
+		addFindings(&score, Detect(data[key]), key, "data["+strconv.Quote(key)+"]",
+			fmt.Sprintf("The key %s", key),
+			"Credentials in ConfigMaps are readable by everyone that can read ConfigMaps in the namespace. Store the value in a Secret instead.")
+	}
+
+	return
+}
@@ -0,0 +1,99 @@
+package security
+
+import (
+	"fmt"
+
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/score/internal"
+	"github.com/younes-bami/kube-score/scorecard"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// dangerousCapabilities are capabilities that can be used to escape the container, or to attack the node or other pods
+var dangerousCapabilities = map[string]struct{}{
+	"ALL":             {},
+	"BPF":             {},
+	"DAC_READ_SEARCH": {},
+	"MAC_ADMIN":       {},
+	"MAC_OVERRIDE":    {},
+	"NET_ADMIN":       {},
+	"NET_RAW":         {},
+	"PERFMON":         {},
+	"SYS_ADMIN":       {},
+	"SYS_BOOT":        {},
+	"SYS_MODULE":      {},
+	"SYS_PTRACE":      {},
+	"SYS_RAWIO":       {},
+	"SYS_TIME":        {},
+}
+
+// containerSecurityContextCapabilities checks that all containers drop ALL capabilities, and only add back allowed capabilities
+func containerSecurityContextCapabilities(allowedCapabilities []string) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	allowed := make(map[string]struct{})
+	for _, capability := range allowedCapabilities {
+		allowed[internal.NormalizeCapability(corev1.Capability(capability))] = struct{}{}
+	}
+
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		pod := ps.GetPodTemplateSpec().Spec
+		allContainers := pod.InitContainers
+		allContainers = append(allContainers, pod.Containers...)
+
+		score.Grade = scorecard.GradeAllOK
+		setGrade := func(grade scorecard.Grade) {
+			if grade < score.Grade {
+				score.Grade = grade
+			}
+		}
+
+		for i, container := range allContainers {
+			capabilitiesPath := internal.ContainerFieldPath(pod, i) + ".securityContext.capabilities"
+
+			capabilities := internal.ContainerCapabilities(container)
+			if !internal.DropsAllCapabilities(capabilities) {
+				setGrade(scorecard.GradeWarning)
+				score.AddCommentWithFieldPath(container.Name, capabilitiesPath+".drop", "The container does not drop all capabilities", "Set securityContext.capabilities.drop to [\"ALL\"], and only add back the capabilities that the container needs.")
+			}
+
+			for j, capability := range capabilities.Add {
+				name := internal.NormalizeCapability(capability)
+				if _, ok := allowed[name]; ok {
+					continue
+				}
+				fieldPath := fmt.Sprintf("%s.add[%d]", capabilitiesPath, j)
+				if _, ok := dangerousCapabilities[name]; ok {
+					setGrade(scorecard.GradeCritical)
+					score.AddCommentWithFieldPath(container.Name, fieldPath, "The container adds the dangerous capability "+name, "This capability can be used to escape the container, or to attack the node. Remove the capability, or allow it with --allow-capability if it is required.")
+				} else {
+					setGrade(scorecard.GradeWarning)
+					score.AddCommentWithFieldPath(container.Name, fieldPath, "The container adds the capability "+name, "Only add the capabilities that the container needs. Remove the capability, or allow it with --allow-capability if it is required.")
+				}
+			}
+		}
+
+		return
+	}
+}
+
+// containerSecurityContextPrivilegeEscalation checks that all containers have disabled privilege escalation
+func containerSecurityContextPrivilegeEscalation(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+	pod := ps.GetPodTemplateSpec().Spec
+	allContainers := pod.InitContainers
+	allContainers = append(allContainers, pod.Containers...)
+
+	score.Grade = scorecard.GradeAllOK
+	for i, container := range allContainers {
+		fieldPath := internal.ContainerFieldPath(pod, i) + ".securityContext.allowPrivilegeEscalation"
+		allowed := internal.AllowPrivilegeEscalation(container)
+		if allowed == nil {
+			if score.Grade > scorecard.GradeWarning {
+				score.Grade = scorecard.GradeWarning
+			}
+			score.AddCommentWithFieldPath(container.Name, fieldPath, "The container does not disable privilege escalation", "Privilege escalation is allowed by default. Set securityContext.allowPrivilegeEscalation to false, to stop processes from gaining more privileges than their parent, for example through setuid binaries.")
+		} else if *allowed {
+			score.Grade = scorecard.GradeCritical
+			score.AddCommentWithFieldPath(container.Name, fieldPath, "The container allows privilege escalation", "Set securityContext.allowPrivilegeEscalation to false, to stop processes from gaining more privileges than their parent, for example through setuid binaries.")
+		}
+	}
+	return
+}
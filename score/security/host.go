@@ -0,0 +1,99 @@
+package security
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/score/internal"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+// hostNamespaceDescriptions describe what the pod can access in each namespace of the node
+var hostNamespaceDescriptions = map[string]string{
+	"network": "The pod can access the network interfaces of the node, and services that are listening on localhost. Set hostNetwork to false.",
+	"PID":     "The pod can see and signal all processes that are running on the node. Set hostPID to false.",
+	"IPC":     "The pod can access the shared memory of all processes that are running on the node. Set hostIPC to false.",
+}
+
+// podHostNamespaces checks that the pod does not share the network, PID or IPC namespace of the node
+func podHostNamespaces(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+	for _, ns := range internal.HostNamespaces(ps.GetPodTemplateSpec().Spec) {
+		score.AddCommentWithFieldPath("", ns.FieldPath, fmt.Sprintf("The pod uses the %s namespace of the node", ns.Name), hostNamespaceDescriptions[ns.Name])
+	}
+
+	if len(score.Comments) > 0 {
+		score.Grade = scorecard.GradeCritical
+	} else {
+		score.Grade = scorecard.GradeAllOK
+	}
+	return
+}
+
+// containerHostPort checks that no container binds a port on the node
+func containerHostPort(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+	for _, port := range internal.HostPorts(ps.GetPodTemplateSpec().Spec) {
+		score.Grade = scorecard.GradeWarning
+		score.AddCommentWithFieldPath(port.Container, port.FieldPath, fmt.Sprintf("The container uses the host port %d", port.Port), "Host ports bypass NetworkPolicies, and limit how many pods that can be scheduled on each node. Expose the pod with a Service instead.")
+	}
+	return
+}
+
+// podHostPathVolumes checks that the pod only uses hostPath volumes that are allowed by a rule
+func podHostPathVolumes(allowlist []config.HostPathRule) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		pod := ps.GetPodTemplateSpec().Spec
+		meta := ps.GetObjectMeta()
+
+		annotations := internal.PodAnnotations(ps)
+
+		var rules []config.HostPathRule
+		for _, rule := range allowlist {
+			if rule.Matches(meta.Namespace, annotations) {
+				rules = append(rules, rule)
+			}
+		}
+
+		allContainers := pod.InitContainers
+		allContainers = append(allContainers, pod.Containers...)
+
+		score.Grade = scorecard.GradeAllOK
+		for _, hostPathVolume := range internal.HostPathVolumes(pod) {
+			volume := hostPathVolume.Volume
+			rule, ok := matchingHostPathRule(rules, volume.HostPath.Path)
+			if !ok {
+				score.Grade = scorecard.GradeCritical
+				score.AddCommentWithFieldPath("", hostPathVolume.FieldPath+".path", fmt.Sprintf("The volume %s mounts %s from the node", volume.Name, volume.HostPath.Path), "HostPath volumes give access to the file system of the node. Use another volume type, or allow the path in the host-path-allowlist of the configuration file.")
+				continue
+			}
+
+			if !rule.ReadOnly {
+				continue
+			}
+			for j, container := range allContainers {
+				for k, mount := range container.VolumeMounts {
+					if mount.Name == volume.Name && !mount.ReadOnly {
+						score.Grade = scorecard.GradeCritical
+						score.AddCommentWithFieldPath(container.Name, fmt.Sprintf("%s.volumeMounts[%d].readOnly", internal.ContainerFieldPath(pod, j), k), fmt.Sprintf("The host path %s is mounted writable", volume.HostPath.Path), fmt.Sprintf("The host path is only allowed to be mounted read-only. Set readOnly to true on the volume mount of %s.", volume.Name))
+					}
+				}
+			}
+		}
+		return
+	}
+}
+
+// matchingHostPathRule returns the first rule that allows the host path. A rule allows its own path, and all paths below it.
+func matchingHostPathRule(rules []config.HostPathRule, hostPath string) (config.HostPathRule, bool) {
+	hostPath = path.Clean(hostPath)
+	for _, rule := range rules {
+		allowed := path.Clean(rule.Path)
+		if hostPath == allowed || strings.HasPrefix(hostPath, strings.TrimSuffix(allowed, "/")+"/") {
+			return rule, true
+		}
+	}
+	return config.HostPathRule{}, false
+}
@@ -0,0 +1,155 @@
+package security
+
+import (
+	"strings"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/score/internal"
+	"github.com/younes-bami/kube-score/scorecard"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	seccompPodAnnotation             = "seccomp.security.alpha.kubernetes.io/pod"
+	seccompContainerAnnotationPrefix = "container.seccomp.security.alpha.kubernetes.io/"
+	seccompDefaultProfileAnnotation  = "seccomp.security.alpha.kubernetes.io/defaultProfileName"
+)
+
+// seccompAnnotationsRemoved is the Kubernetes version in which the alpha seccomp annotations stopped having any effect
+var seccompAnnotationsRemoved = config.Semver{Major: 1, Minor: 27}
+
+// seccompProfile is the seccomp profile of a container, and where it was configured
+type seccompProfile struct {
+	profile   corev1.SeccompProfile
+	fieldPath string
+
+	// source identifies where the profile was configured, profiles with the same source are shared between containers
+	source string
+
+	// forContainer is true if the profile was configured for the container, and not inherited from the pod
+	forContainer bool
+}
+
+// podSeccompProfile checks that all containers have a seccomp profile, either set on the container or inherited from the pod.
+// The alpha annotations are only used when the seccompProfile fields are not set, and the Kubernetes version still supports them.
+func podSeccompProfile(kubeVersion config.Semver) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	annotationsSupported := kubeVersion.LessThan(seccompAnnotationsRemoved)
+
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		template := ps.GetPodTemplateSpec()
+		pod := template.Spec
+		allContainers := pod.InitContainers
+		allContainers = append(allContainers, pod.Containers...)
+
+		score.Grade = scorecard.GradeAllOK
+		setGrade := func(grade scorecard.Grade) {
+			if grade < score.Grade {
+				score.Grade = grade
+			}
+		}
+
+		// Profiles that are inherited by multiple containers are only validated once
+		validated := make(map[string]struct{})
+
+		for i, container := range allContainers {
+			containerPath := internal.ContainerFieldPath(pod, i)
+			profile, fromAnnotation := effectiveSeccompProfile(template, container, containerPath, annotationsSupported)
+
+			if profile == nil {
+				setGrade(scorecard.GradeWarning)
+				if fromAnnotation {
+					score.AddCommentWithFieldPath(container.Name, "metadata.annotations", "The seccomp annotation has no effect", "The seccomp.security.alpha.kubernetes.io annotations are not supported since Kubernetes v1.27. Set securityContext.seccompProfile on the pod or on the container instead.")
+				} else {
+					score.AddCommentWithFieldPath(container.Name, containerPath+".securityContext.seccompProfile", "The container has no seccomp profile", "Running containers with Seccomp is recommended to reduce the kernel attack surface. Set securityContext.seccompProfile.type to RuntimeDefault, either on the pod or on the container.")
+				}
+				continue
+			}
+
+			if _, ok := validated[profile.source]; ok {
+				continue
+			}
+			validated[profile.source] = struct{}{}
+
+			path := ""
+			if profile.forContainer {
+				path = container.Name
+			}
+
+			switch profile.profile.Type {
+			case corev1.SeccompProfileTypeRuntimeDefault:
+			case corev1.SeccompProfileTypeUnconfined:
+				setGrade(scorecard.GradeWarning)
+				score.AddCommentWithFieldPath(path, profile.fieldPath, "The seccomp profile is Unconfined", "Running containers with Seccomp is recommended to reduce the kernel attack surface. Use the RuntimeDefault profile, or a Localhost profile.")
+			case corev1.SeccompProfileTypeLocalhost:
+				if summary := invalidLocalhostProfile(profile.profile.LocalhostProfile); summary != "" {
+					setGrade(scorecard.GradeCritical)
+					score.AddCommentWithFieldPath(path, profile.fieldPath, summary, "The localhostProfile must be a relative path to a profile in the seccomp directory of the kubelet, such as 'profiles/audit.json'.")
+				}
+			default:
+				setGrade(scorecard.GradeCritical)
+				score.AddCommentWithFieldPath(path, profile.fieldPath, "The seccomp profile type "+string(profile.profile.Type)+" is not valid", "The type must be RuntimeDefault, Localhost or Unconfined.")
+			}
+		}
+
+		return
+	}
+}
+
+// effectiveSeccompProfile returns the seccomp profile that applies to the container. The profile of the container takes
+// precedence over the profile of the pod, and the fields take precedence over the annotations.
+// If no profile applies, ignoredAnnotation is true if the pod has annotations that are no longer supported.
+func effectiveSeccompProfile(template corev1.PodTemplateSpec, container corev1.Container, containerPath string, annotationsSupported bool) (profile *seccompProfile, ignoredAnnotation bool) {
+	if container.SecurityContext != nil && container.SecurityContext.SeccompProfile != nil {
+		fieldPath := containerPath + ".securityContext.seccompProfile"
+		return &seccompProfile{*container.SecurityContext.SeccompProfile, fieldPath, fieldPath, true}, false
+	}
+	if template.Spec.SecurityContext != nil && template.Spec.SecurityContext.SeccompProfile != nil {
+		fieldPath := "spec.securityContext.seccompProfile"
+		return &seccompProfile{*template.Spec.SecurityContext.SeccompProfile, fieldPath, fieldPath, false}, false
+	}
+
+	containerAnnotation := seccompContainerAnnotationPrefix + container.Name
+	for _, key := range []string{containerAnnotation, seccompPodAnnotation, seccompDefaultProfileAnnotation} {
+		value, ok := template.Annotations[key]
+		if !ok {
+			continue
+		}
+		if !annotationsSupported {
+			return nil, true
+		}
+		return &seccompProfile{seccompAnnotationProfile(value), "metadata.annotations", key, key == containerAnnotation}, false
+	}
+
+	return nil, false
+}
+
+// seccompAnnotationProfile converts the value of an alpha seccomp annotation to a profile
+func seccompAnnotationProfile(value string) corev1.SeccompProfile {
+	switch {
+	case value == "runtime/default" || value == "docker/default":
+		return corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	case value == "unconfined":
+		return corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined}
+	case strings.HasPrefix(value, "localhost/"):
+		localhostProfile := strings.TrimPrefix(value, "localhost/")
+		return corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost, LocalhostProfile: &localhostProfile}
+	}
+	return corev1.SeccompProfile{Type: corev1.SeccompProfileType(value)}
+}
+
+// invalidLocalhostProfile returns a summary of why the path of a Localhost profile is invalid, or an empty string if it is valid
+func invalidLocalhostProfile(localhostProfile *string) string {
+	if localhostProfile == nil || *localhostProfile == "" {
+		return "The Localhost seccomp profile has no localhostProfile"
+	}
+	if strings.HasPrefix(*localhostProfile, "/") {
+		return "The Localhost seccomp profile has an absolute path"
+	}
+	for _, part := range strings.Split(*localhostProfile, "/") {
+		if part == ".." {
+			return "The Localhost seccomp profile path contains '..'"
+		}
+	}
+	return ""
+}
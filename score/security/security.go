@@ -1,38 +1,52 @@
 package security
 
 import (
+	"github.com/younes-bami/kube-score/config"
 	ks "github.com/younes-bami/kube-score/domain"
 	"github.com/younes-bami/kube-score/score/checks"
+	"github.com/younes-bami/kube-score/score/internal"
 	"github.com/younes-bami/kube-score/scorecard"
 	corev1 "k8s.io/api/core/v1"
 )
 
-func Register(allChecks *checks.Checks) {
+func Register(allChecks *checks.Checks, cnf config.Configuration) {
+	allowedCapabilities := cnf.AllowedCapabilities
+	if allowedCapabilities == nil {
+		allowedCapabilities = config.DefaultAllowedCapabilities
+	}
+
 	allChecks.RegisterPodCheck("Container Security Context User Group ID", `Makes sure that all pods have a security context with valid UID and GID set `, containerSecurityContextUserGroupID)
 	allChecks.RegisterPodCheck("Container Security Context Privileged", "Makes sure that all pods have a unprivileged security context set", containerSecurityContextPrivileged)
+	allChecks.RegisterPodCheck("Pod Host Namespaces", "Makes sure that pods do not use the network, PID or IPC namespaces of the node", podHostNamespaces)
+	allChecks.RegisterPodCheck("Container Host Port", "Makes sure that containers do not bind ports on the node with hostPort", containerHostPort)
+	allChecks.RegisterPodCheck("Pod HostPath Volumes", "Makes sure that pods only use hostPath volumes that are allowed by the host-path-allowlist of the configuration file. Rules can be limited to namespaces, or to objects with an annotation, and can require the volume to be mounted read-only.", podHostPathVolumes(cnf.HostPathAllowlist))
 	allChecks.RegisterPodCheck("Container Security Context ReadOnlyRootFilesystem", "Makes sure that all pods have a security context with read only filesystem set", containerSecurityContextReadOnlyRootFilesystem)
 
-	allChecks.RegisterOptionalPodCheck("Container Seccomp Profile", `Makes sure that all pods have at a seccomp policy configured.`, podSeccompProfile)
+	allChecks.RegisterOptionalPodCheck("Container Security Context Capabilities", `Makes sure that all containers drop all capabilities, and only add back the capabilities that are allowed with --allow-capability (NET_BIND_SERVICE by default). Dangerous capabilities, such as SYS_ADMIN and NET_RAW, are critical.`, containerSecurityContextCapabilities(allowedCapabilities))
+	allChecks.RegisterOptionalPodCheck("Container Security Context Privilege Escalation", "Makes sure that all containers have set allowPrivilegeEscalation to false", containerSecurityContextPrivilegeEscalation)
+	allChecks.RegisterOptionalPodCheck("Container Seccomp Profile", `Makes sure that all containers have a seccomp profile configured, either on the container or on the pod. The alpha seccomp annotations are only used if the --kubernetes-version is older than v1.27.`, podSeccompProfile(cnf.KubernetesVersion))
 }
 
 // containerSecurityContextReadOnlyRootFilesystem checks for pods using writeable root filesystems
 func containerSecurityContextReadOnlyRootFilesystem(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
-	allContainers := ps.GetPodTemplateSpec().Spec.InitContainers
-	allContainers = append(allContainers, ps.GetPodTemplateSpec().Spec.Containers...)
+	pod := ps.GetPodTemplateSpec().Spec
+	allContainers := pod.InitContainers
+	allContainers = append(allContainers, pod.Containers...)
 
 	noContextSet := false
 	hasWritableRootFS := false
 
-	for _, container := range allContainers {
+	for i, container := range allContainers {
+		securityContext := internal.ContainerFieldPath(pod, i) + ".securityContext"
 		if container.SecurityContext == nil {
 			noContextSet = true
-			score.AddComment(container.Name, "Container has no configured security context", "Set securityContext to run the container in a more secure context.")
+			score.AddCommentWithFieldPath(container.Name, securityContext, "Container has no configured security context", "Set securityContext to run the container in a more secure context.")
 			continue
 		}
 		sec := container.SecurityContext
 		if sec.ReadOnlyRootFilesystem == nil || !*sec.ReadOnlyRootFilesystem {
 			hasWritableRootFS = true
-			score.AddComment(container.Name, "The pod has a container with a writable root filesystem", "Set securityContext.readOnlyRootFilesystem to true")
+			score.AddCommentWithFieldPath(container.Name, securityContext+".readOnlyRootFilesystem", "The pod has a container with a writable root filesystem", "Set securityContext.readOnlyRootFilesystem to true")
 		}
 	}
 
@@ -47,13 +61,14 @@ func containerSecurityContextReadOnlyRootFilesystem(ps ks.PodSpecer) (score scor
 
 // containerSecurityContextPrivileged checks for privileged containers
 func containerSecurityContextPrivileged(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
-	allContainers := ps.GetPodTemplateSpec().Spec.InitContainers
-	allContainers = append(allContainers, ps.GetPodTemplateSpec().Spec.Containers...)
+	pod := ps.GetPodTemplateSpec().Spec
+	allContainers := pod.InitContainers
+	allContainers = append(allContainers, pod.Containers...)
 	hasPrivileged := false
-	for _, container := range allContainers {
+	for i, container := range allContainers {
 		if container.SecurityContext != nil && container.SecurityContext.Privileged != nil && *container.SecurityContext.Privileged {
 			hasPrivileged = true
-			score.AddComment(container.Name, "The container is privileged", "Set securityContext.privileged to false. Privileged containers can access all devices on the host, and grants almost the same access as non-containerized processes on the host.")
+			score.AddCommentWithFieldPath(container.Name, internal.ContainerFieldPath(pod, i)+".securityContext.privileged", "The container is privileged", "Set securityContext.privileged to false. Privileged containers can access all devices on the host, and grants almost the same access as non-containerized processes on the host.")
 		}
 	}
 	if hasPrivileged {
@@ -66,16 +81,18 @@ func containerSecurityContextPrivileged(ps ks.PodSpecer) (score scorecard.TestSc
 
 // containerSecurityContextUserGroupID checks that the user and group are valid ( > 10000) in the security context
 func containerSecurityContextUserGroupID(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
-	allContainers := ps.GetPodTemplateSpec().Spec.InitContainers
-	allContainers = append(allContainers, ps.GetPodTemplateSpec().Spec.Containers...)
-	podSecurityContext := ps.GetPodTemplateSpec().Spec.SecurityContext
+	pod := ps.GetPodTemplateSpec().Spec
+	allContainers := pod.InitContainers
+	allContainers = append(allContainers, pod.Containers...)
+	podSecurityContext := pod.SecurityContext
 	noContextSet := false
 	hasLowUserID := false
 	hasLowGroupID := false
-	for _, container := range allContainers {
+	for i, container := range allContainers {
+		securityContext := internal.ContainerFieldPath(pod, i) + ".securityContext"
 		if container.SecurityContext == nil && podSecurityContext == nil {
 			noContextSet = true
-			score.AddComment(container.Name, "Container has no configured security context", "Set securityContext to run the container in a more secure context.")
+			score.AddCommentWithFieldPath(container.Name, securityContext, "Container has no configured security context", "Set securityContext to run the container in a more secure context.")
 			continue
 		}
 		sec := container.SecurityContext
@@ -93,12 +110,12 @@ func containerSecurityContextUserGroupID(ps ks.PodSpecer) (score scorecard.TestS
 		}
 		if sec.RunAsUser == nil || *sec.RunAsUser < 10000 {
 			hasLowUserID = true
-			score.AddComment(container.Name, "The container is running with a low user ID", "A userid above 10 000 is recommended to avoid conflicts with the host. Set securityContext.runAsUser to a value > 10000")
+			score.AddCommentWithFieldPath(container.Name, securityContext+".runAsUser", "The container is running with a low user ID", "A userid above 10 000 is recommended to avoid conflicts with the host. Set securityContext.runAsUser to a value > 10000")
 		}
 
 		if sec.RunAsGroup == nil || *sec.RunAsGroup < 10000 {
 			hasLowGroupID = true
-			score.AddComment(container.Name, "The container running with a low group ID", "A groupid above 10 000 is recommended to avoid conflicts with the host. Set securityContext.runAsGroup to a value > 10000")
+			score.AddCommentWithFieldPath(container.Name, securityContext+".runAsGroup", "The container running with a low group ID", "A groupid above 10 000 is recommended to avoid conflicts with the host. Set securityContext.runAsGroup to a value > 10000")
 		}
 	}
 	if noContextSet || hasLowUserID || hasLowGroupID {
@@ -108,24 +125,3 @@ func containerSecurityContextUserGroupID(ps ks.PodSpecer) (score scorecard.TestS
 	}
 	return
 }
-
-// podSeccompProfile checks that a Seccommp profile is configured for the pod
-func podSeccompProfile(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
-	metadata := ps.GetPodTemplateSpec().ObjectMeta
-
-	seccompAnnotated := false
-	if metadata.Annotations != nil {
-		if _, ok := metadata.Annotations["seccomp.security.alpha.kubernetes.io/defaultProfileName"]; ok {
-			seccompAnnotated = true
-		}
-	}
-
-	if !seccompAnnotated {
-		score.Grade = scorecard.GradeWarning
-		score.AddComment(metadata.Name, "The pod has not configured Seccomp for its containers", "Running containers with Seccomp is recommended to reduce the kernel attack surface")
-	} else {
-		score.Grade = scorecard.GradeAllOK
-	}
-
-	return
-}
@@ -30,6 +30,15 @@ func TestContainerSeccompMissingNotRunByDefault(t *testing.T) {
 	assert.True(t, skipped)
 }
 
+func TestContainerSeccompEnabledIsNotSkipped(t *testing.T) {
+	t.Parallel()
+	skipped := wasSkipped(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-seccomp-no-annotation.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-seccomp-profile": {}},
+	}, "Container Seccomp Profile")
+	assert.False(t, skipped)
+}
+
 func TestContainerSeccompAllGood(t *testing.T) {
 	t.Parallel()
 
@@ -71,9 +80,11 @@ func TestContainerSecurityContextUserGroupIDLowGroup(t *testing.T) {
 		EnabledOptionalTests: optionalChecks,
 	}, "Container Security Context User Group ID", scorecard.GradeCritical)
 	assert.Contains(t, comments, scorecard.TestScoreComment{
-		Path:        "foobar",
-		Summary:     "The container running with a low group ID",
-		Description: "A groupid above 10 000 is recommended to avoid conflicts with the host. Set securityContext.runAsGroup to a value > 10000",
+		Path:         "foobar",
+		Summary:      "The container running with a low group ID",
+		Description:  "A groupid above 10 000 is recommended to avoid conflicts with the host. Set securityContext.runAsGroup to a value > 10000",
+		FieldPath:    "spec.containers[0].securityContext.runAsGroup",
+		FileLocation: ks.FileLocation{Name: "testdata/pod-security-context-low-group-id.yaml", Line: 11, Column: 7},
 	})
 }
 
@@ -86,9 +97,11 @@ func TestContainerSecurityContextUserGroupIDLowUser(t *testing.T) {
 		EnabledOptionalTests: optionalChecks,
 	}, "Container Security Context User Group ID", scorecard.GradeCritical)
 	assert.Contains(t, comments, scorecard.TestScoreComment{
-		Path:        "foobar",
-		Summary:     "The container is running with a low user ID",
-		Description: "A userid above 10 000 is recommended to avoid conflicts with the host. Set securityContext.runAsUser to a value > 10000",
+		Path:         "foobar",
+		Summary:      "The container is running with a low user ID",
+		Description:  "A userid above 10 000 is recommended to avoid conflicts with the host. Set securityContext.runAsUser to a value > 10000",
+		FieldPath:    "spec.containers[0].securityContext.runAsUser",
+		FileLocation: ks.FileLocation{Name: "testdata/pod-security-context-low-user-id.yaml", Line: 10, Column: 7},
 	})
 }
 
@@ -101,9 +114,11 @@ func TestContainerSecurityContextUserGroupIDNoSecurityContext(t *testing.T) {
 		EnabledOptionalTests: optionalChecks,
 	}, "Container Security Context User Group ID", scorecard.GradeCritical)
 	assert.Contains(t, comments, scorecard.TestScoreComment{
-		Path:        "foobar",
-		Summary:     "Container has no configured security context",
-		Description: "Set securityContext to run the container in a more secure context.",
+		Path:         "foobar",
+		Summary:      "Container has no configured security context",
+		Description:  "Set securityContext to run the container in a more secure context.",
+		FieldPath:    "spec.containers[0].securityContext",
+		FileLocation: ks.FileLocation{Name: "testdata/pod-security-context-nosecuritycontext.yaml", Line: 7, Column: 5},
 	})
 }
 
@@ -127,9 +142,11 @@ func TestContainerSecurityContextPrivilegedPrivileged(t *testing.T) {
 		EnabledOptionalTests: optionalChecks,
 	}, "Container Security Context Privileged", scorecard.GradeCritical)
 	assert.Contains(t, comments, scorecard.TestScoreComment{
-		Path:        "foobar",
-		Summary:     "The container is privileged",
-		Description: "Set securityContext.privileged to false. Privileged containers can access all devices on the host, and grants almost the same access as non-containerized processes on the host.",
+		Path:         "foobar",
+		Summary:      "The container is privileged",
+		Description:  "Set securityContext.privileged to false. Privileged containers can access all devices on the host, and grants almost the same access as non-containerized processes on the host.",
+		FieldPath:    "spec.containers[0].securityContext.privileged",
+		FileLocation: ks.FileLocation{Name: "testdata/pod-security-context-privileged.yaml", Line: 10, Column: 7},
 	})
 }
 
@@ -153,9 +170,11 @@ func TestContainerSecurityContextReadOnlyRootFilesystemWriteable(t *testing.T) {
 		EnabledOptionalTests: optionalChecks,
 	}, "Container Security Context ReadOnlyRootFilesystem", scorecard.GradeCritical)
 	assert.Contains(t, comments, scorecard.TestScoreComment{
-		Path:        "foobar",
-		Summary:     "The pod has a container with a writable root filesystem",
-		Description: "Set securityContext.readOnlyRootFilesystem to true",
+		Path:         "foobar",
+		Summary:      "The pod has a container with a writable root filesystem",
+		Description:  "Set securityContext.readOnlyRootFilesystem to true",
+		FieldPath:    "spec.containers[0].securityContext.readOnlyRootFilesystem",
+		FileLocation: ks.FileLocation{Name: "testdata/pod-security-context-writeablerootfilesystem.yaml", Line: 9, Column: 5},
 	})
 }
 
@@ -168,8 +187,176 @@ func TestContainerSecurityContextReadOnlyRootFilesystemNoSecurityContext(t *test
 		EnabledOptionalTests: optionalChecks,
 	}, "Container Security Context ReadOnlyRootFilesystem", scorecard.GradeCritical)
 	assert.Contains(t, comments, scorecard.TestScoreComment{
-		Path:        "foobar",
-		Summary:     "Container has no configured security context",
-		Description: "Set securityContext to run the container in a more secure context.",
+		Path:         "foobar",
+		Summary:      "Container has no configured security context",
+		Description:  "Set securityContext to run the container in a more secure context.",
+		FieldPath:    "spec.containers[0].securityContext",
+		FileLocation: ks.FileLocation{Name: "testdata/pod-security-context-nosecuritycontext.yaml", Line: 7, Column: 5},
 	})
 }
+
+func TestContainerSeccompProfileFields(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-seccomp-profile-fields.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-seccomp-profile": {}},
+		KubernetesVersion:    config.Semver{Major: 1, Minor: 27},
+	}, "Container Seccomp Profile", scorecard.GradeAllOK)
+}
+
+func TestContainerSeccompProfileInvalid(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-seccomp-profile-invalid.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-seccomp-profile": {}},
+		KubernetesVersion:    config.Semver{Major: 1, Minor: 27},
+	}, "Container Seccomp Profile", scorecard.GradeCritical)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "", comments[0].Path)
+	assert.Equal(t, "The seccomp profile is Unconfined", comments[0].Summary)
+	assert.Equal(t, "spec.securityContext.seccompProfile", comments[0].FieldPath)
+	assert.Equal(t, "absolute", comments[1].Path)
+	assert.Equal(t, "The Localhost seccomp profile has an absolute path", comments[1].Summary)
+	assert.Equal(t, "spec.containers[2].securityContext.seccompProfile", comments[1].FieldPath)
+}
+
+func TestContainerSeccompAnnotationIgnoredSince127(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-seccomp-annotated.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-seccomp-profile": {}},
+		KubernetesVersion:    config.Semver{Major: 1, Minor: 27},
+	}, "Container Seccomp Profile", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The seccomp annotation has no effect", comments[0].Summary)
+}
+
+func TestContainerSeccompAnnotationBefore127(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-seccomp-annotated.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-seccomp-profile": {}},
+		KubernetesVersion:    config.Semver{Major: 1, Minor: 26},
+	}, "Container Seccomp Profile", scorecard.GradeAllOK)
+}
+
+func TestContainerSecurityContextCapabilities(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-capabilities.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-security-context-capabilities": {}},
+	}, "Container Security Context Capabilities", scorecard.GradeCritical)
+	assert.Len(t, comments, 4)
+	assert.Equal(t, "bad", comments[0].Path)
+	assert.Equal(t, "The container does not drop all capabilities", comments[0].Summary)
+	assert.Equal(t, "The container adds the dangerous capability SYS_ADMIN", comments[1].Summary)
+	assert.Equal(t, "spec.containers[1].securityContext.capabilities.add[0]", comments[1].FieldPath)
+	assert.Equal(t, "The container adds the capability CHOWN", comments[2].Summary)
+	assert.Equal(t, "unset", comments[3].Path)
+	assert.Equal(t, "The container does not drop all capabilities", comments[3].Summary)
+}
+
+func TestContainerSecurityContextCapabilitiesAllowed(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-capabilities.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-security-context-capabilities": {}},
+		AllowedCapabilities:  []string{"SYS_ADMIN", "CAP_CHOWN"},
+	}, "Container Security Context Capabilities", scorecard.GradeWarning)
+	assert.Len(t, comments, 3)
+	assert.Equal(t, "The container adds the capability NET_BIND_SERVICE", comments[0].Summary)
+}
+
+func TestContainerSecurityContextCapabilitiesAllGood(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-capabilities-all-good.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-security-context-capabilities": {}},
+	}, "Container Security Context Capabilities", scorecard.GradeAllOK)
+}
+
+func TestContainerSecurityContextPrivilegeEscalation(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-capabilities.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-security-context-privilege-escalation": {}},
+	}, "Container Security Context Privilege Escalation", scorecard.GradeCritical)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "bad", comments[0].Path)
+	assert.Equal(t, "The container allows privilege escalation", comments[0].Summary)
+	assert.Equal(t, "unset", comments[1].Path)
+	assert.Equal(t, "The container does not disable privilege escalation", comments[1].Summary)
+}
+
+func TestContainerSecurityContextCapabilitiesNotEnabled(t *testing.T) {
+	t.Parallel()
+	assert.True(t, wasSkipped(t, config.Configuration{
+		AllFiles: []ks.NamedReader{testFile("pod-capabilities.yaml")},
+	}, "Container Security Context Capabilities"))
+	assert.True(t, wasSkipped(t, config.Configuration{
+		AllFiles: []ks.NamedReader{testFile("pod-capabilities.yaml")},
+	}, "Container Security Context Privilege Escalation"))
+}
+
+func TestContainerSecurityContextPrivilegeEscalationAllGood(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:             []ks.NamedReader{testFile("pod-capabilities-all-good.yaml")},
+		EnabledOptionalTests: map[string]struct{}{"container-security-context-privilege-escalation": {}},
+	}, "Container Security Context Privilege Escalation", scorecard.GradeAllOK)
+}
+
+var hostPathAllowlist = []config.HostPathRule{
+	{Path: "/var/log", ReadOnly: true, Annotation: "kube-score/node-agent"},
+	{Path: "/var/lib/docker", ReadOnly: true, Namespaces: []string{"logging"}},
+}
+
+func TestPodHostNamespaces(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "host-access-daemonset.yaml", "Pod Host Namespaces", scorecard.GradeCritical)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "spec.template.spec.hostNetwork", comments[0].FieldPath)
+	assert.Equal(t, "spec.template.spec.hostPID", comments[1].FieldPath)
+
+	testExpectedScore(t, "host-access-deployment.yaml", "Pod Host Namespaces", scorecard.GradeAllOK)
+}
+
+func TestContainerHostPort(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "host-access-daemonset.yaml", "Container Host Port", scorecard.GradeWarning)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The container uses the host port 2020", comments[0].Summary)
+
+	testExpectedScore(t, "host-access-deployment.yaml", "Container Host Port", scorecard.GradeAllOK)
+}
+
+func TestPodHostPathVolumesAllowlist(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("host-access-daemonset.yaml")},
+		HostPathAllowlist: hostPathAllowlist,
+	}, "Pod HostPath Volumes", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "shipper", comments[0].Path)
+	assert.Equal(t, "The host path /var/lib/docker is mounted writable", comments[0].Summary)
+	assert.Equal(t, "spec.template.spec.containers[0].volumeMounts[1].readOnly", comments[0].FieldPath)
+}
+
+func TestPodHostPathVolumesNotAllowedWithoutAnnotation(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("host-access-deployment.yaml")},
+		HostPathAllowlist: hostPathAllowlist,
+	}, "Pod HostPath Volumes", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The volume logs mounts /var/log from the node", comments[0].Summary)
+	assert.Equal(t, "spec.template.spec.volumes[0].hostPath.path", comments[0].FieldPath)
+}
+
+func TestPodHostPathVolumesAllowedInNamespace(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("host-access-deployment.yaml")},
+		HostPathAllowlist: []config.HostPathRule{{Path: "/var", Namespaces: []string{"apps"}}},
+	}, "Pod HostPath Volumes", scorecard.GradeAllOK)
+}
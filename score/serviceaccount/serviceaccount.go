@@ -0,0 +1,101 @@
+package serviceaccount
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/score/checks"
+	"github.com/younes-bami/kube-score/score/internal"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+func Register(allChecks *checks.Checks, serviceAccounts ks.ServiceAccounts) {
+	allChecks.RegisterOptionalPodCheck("Pod Default ServiceAccount", `Makes sure that pods do not use the default ServiceAccount of the namespace`, podDefaultServiceAccount)
+	allChecks.RegisterOptionalPodCheck("Pod ServiceAccount Token Automount", `Makes sure that pods do not automount a ServiceAccount token. Automounting can be disabled on the pod, or on the ServiceAccount.`, podServiceAccountTokenAutomount(serviceAccounts.ServiceAccounts()))
+	allChecks.RegisterOptionalPodCheck("Pod ServiceAccount Exists", `Makes sure that the serviceAccountName of pods refers to a ServiceAccount in the input. Enable it when the ServiceAccounts are deployed together with the workloads.`, podServiceAccountExists(serviceAccounts.ServiceAccounts()))
+}
+
+// serviceAccountName returns the name of the ServiceAccount that the pod runs as, and the field that it is set in
+func serviceAccountName(spec corev1.PodSpec) (name string, fieldPath string) {
+	if spec.ServiceAccountName != "" {
+		return spec.ServiceAccountName, "spec.serviceAccountName"
+	}
+	// serviceAccount is a deprecated alias of serviceAccountName
+	if spec.DeprecatedServiceAccount != "" {
+		return spec.DeprecatedServiceAccount, "spec.serviceAccount"
+	}
+	return "default", "spec.serviceAccountName"
+}
+
+// findServiceAccount returns the ServiceAccount with the name in the namespace, or nil if it is not in the input.
+// Objects without a namespace are in the default namespace.
+func findServiceAccount(serviceAccounts []ks.ServiceAccount, namespace, name string) *corev1.ServiceAccount {
+	for _, s := range serviceAccounts {
+		sa := s.ServiceAccount()
+		if internal.Namespace(sa.Namespace) == internal.Namespace(namespace) && sa.Name == name {
+			return &sa
+		}
+	}
+	return nil
+}
+
+// podDefaultServiceAccount checks that the pod runs as a dedicated ServiceAccount
+func podDefaultServiceAccount(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+	name, fieldPath := serviceAccountName(ps.GetPodTemplateSpec().Spec)
+	if name == "default" {
+		score.Grade = scorecard.GradeWarning
+		score.AddCommentWithFieldPath("", fieldPath, "The pod uses the default ServiceAccount", "All pods in the namespace share the permissions of the default ServiceAccount. Create a dedicated ServiceAccount for the workload, and set serviceAccountName.")
+		return
+	}
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+// podServiceAccountTokenAutomount checks that the ServiceAccount token is not mounted in the pod.
+// The setting on the pod takes precedence over the setting on the ServiceAccount, and tokens are mounted if neither is set.
+func podServiceAccountTokenAutomount(serviceAccounts []ks.ServiceAccount) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		spec := ps.GetPodTemplateSpec().Spec
+
+		if spec.AutomountServiceAccountToken != nil {
+			if *spec.AutomountServiceAccountToken {
+				score.Grade = scorecard.GradeWarning
+				score.AddCommentWithFieldPath("", "spec.automountServiceAccountToken", "The pod automounts the ServiceAccount token", "Set automountServiceAccountToken to false, unless the pod needs to access the Kubernetes API. A leaked token can be used to access the API with the permissions of the ServiceAccount.")
+				return
+			}
+			score.Grade = scorecard.GradeAllOK
+			return
+		}
+
+		name, _ := serviceAccountName(spec)
+		if sa := findServiceAccount(serviceAccounts, ps.GetObjectMeta().Namespace, name); sa != nil && sa.AutomountServiceAccountToken != nil && !*sa.AutomountServiceAccountToken {
+			score.Grade = scorecard.GradeAllOK
+			return
+		}
+
+		score.Grade = scorecard.GradeWarning
+		score.AddCommentWithFieldPath("", "spec.automountServiceAccountToken", "The pod automounts the ServiceAccount token", "The token is mounted by default. Set automountServiceAccountToken to false on the pod or on the ServiceAccount, unless the pod needs to access the Kubernetes API. A leaked token can be used to access the API with the permissions of the ServiceAccount.")
+		return
+	}
+}
+
+// podServiceAccountExists checks that the ServiceAccount of the pod is in the input.
+// The default ServiceAccount is created by Kubernetes in all namespaces, and is assumed to exist.
+func podServiceAccountExists(serviceAccounts []ks.ServiceAccount) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		name, fieldPath := serviceAccountName(ps.GetPodTemplateSpec().Spec)
+		if name == "default" {
+			score.Grade = scorecard.GradeAllOK
+			return
+		}
+
+		if findServiceAccount(serviceAccounts, ps.GetObjectMeta().Namespace, name) == nil {
+			score.Grade = scorecard.GradeCritical
+			score.AddCommentWithFieldPath("", fieldPath, "The ServiceAccount "+name+" does not exist", "No ServiceAccount with this name was found in the same namespace as the pod. Pods can not be created with a ServiceAccount that does not exist.")
+			return
+		}
+
+		score.Grade = scorecard.GradeAllOK
+		return
+	}
+}
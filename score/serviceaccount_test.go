@@ -0,0 +1,77 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+// serviceAccountConfig returns a new configuration that scores serviceaccount.yaml with the optional checks enabled
+func serviceAccountConfig() config.Configuration {
+	return config.Configuration{
+		AllFiles: []ks.NamedReader{testFile("serviceaccount.yaml")},
+		EnabledOptionalTests: map[string]struct{}{
+			"pod-default-serviceaccount":         {},
+			"pod-serviceaccount-token-automount": {},
+			"pod-serviceaccount-exists":          {},
+		},
+	}
+}
+
+func TestPodDefaultServiceAccount(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, serviceAccountConfig(), "Deployment/apps/v1/apps/uses-app", "pod-default-serviceaccount").Grade)
+
+	res := testObjectCheck(t, serviceAccountConfig(), "Pod/v1/apps/uses-default", "pod-default-serviceaccount")
+	assert.Equal(t, scorecard.GradeWarning, res.Grade)
+	assert.Len(t, res.Comments, 1)
+	assert.Equal(t, "spec.serviceAccountName", res.Comments[0].FieldPath)
+}
+
+func TestPodServiceAccountTokenAutomount(t *testing.T) {
+	t.Parallel()
+
+	// Disabled on the ServiceAccount
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, serviceAccountConfig(), "Deployment/apps/v1/apps/uses-app", "pod-serviceaccount-token-automount").Grade)
+
+	// Disabled on the pod
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, serviceAccountConfig(), "Pod/v1/other/other-namespace", "pod-serviceaccount-token-automount").Grade)
+
+	// Enabled on the pod
+	res := testObjectCheck(t, serviceAccountConfig(), "Deployment/apps/v1/apps/uses-missing", "pod-serviceaccount-token-automount")
+	assert.Equal(t, scorecard.GradeWarning, res.Grade)
+	assert.Equal(t, "spec.template.spec.automountServiceAccountToken", res.Comments[0].FieldPath)
+
+	// Not set
+	assert.Equal(t, scorecard.GradeWarning, testObjectCheck(t, serviceAccountConfig(), "Pod/v1/apps/uses-default", "pod-serviceaccount-token-automount").Grade)
+}
+
+func TestPodServiceAccountExists(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, serviceAccountConfig(), "Deployment/apps/v1/apps/uses-app", "pod-serviceaccount-exists").Grade)
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, serviceAccountConfig(), "Pod/v1/apps/uses-default", "pod-serviceaccount-exists").Grade)
+
+	res := testObjectCheck(t, serviceAccountConfig(), "Deployment/apps/v1/apps/uses-missing", "pod-serviceaccount-exists")
+	assert.Equal(t, scorecard.GradeCritical, res.Grade)
+	assert.Equal(t, "The ServiceAccount missing does not exist", res.Comments[0].Summary)
+	assert.Equal(t, 41, res.Comments[0].FileLocation.Line)
+
+	// The ServiceAccount is in another namespace
+	assert.Equal(t, scorecard.GradeCritical, testObjectCheck(t, serviceAccountConfig(), "Pod/v1/other/other-namespace", "pod-serviceaccount-exists").Grade)
+
+	// The ServiceAccount without a namespace is in the default namespace
+	assert.Equal(t, scorecard.GradeAllOK, testObjectCheck(t, serviceAccountConfig(), "Pod/v1/default/uses-worker", "pod-serviceaccount-exists").Grade)
+}
+
+func TestPodServiceAccountChecksNotEnabled(t *testing.T) {
+	t.Parallel()
+	for _, check := range []string{"Pod Default ServiceAccount", "Pod ServiceAccount Token Automount", "Pod ServiceAccount Exists"} {
+		assert.True(t, wasSkipped(t, config.Configuration{
+			AllFiles: []ks.NamedReader{testFile("serviceaccount.yaml")},
+		}, check), check)
+	}
+}
@@ -0,0 +1,44 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+func TestSeverityOverrideWarningToCritical(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("service-type-nodeport.yaml")},
+		SeverityOverrides: map[string]config.Severity{"service-type": config.SeverityCritical},
+	}, "Service Type", scorecard.GradeCritical)
+}
+
+func TestSeverityOverrideCriticalToWarning(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("pod-test-resources-none.yaml")},
+		SeverityOverrides: map[string]config.Severity{"container-resources": config.SeverityWarning},
+	}, "Container Resources", scorecard.GradeWarning)
+}
+
+func TestSeverityOverrideInfo(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("pod-test-resources-none.yaml")},
+		SeverityOverrides: map[string]config.Severity{"container-resources": config.SeverityInfo},
+	}, "Container Resources", scorecard.GradeAlmostOK)
+	if len(comments) == 0 {
+		t.Error("expected the findings to be kept")
+	}
+}
+
+func TestSeverityOverrideDoesNotAffectPassingChecks(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(t, config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("service-type-clusterip.yaml")},
+		SeverityOverrides: map[string]config.Severity{"service-type": config.SeverityCritical},
+	}, "Service Type", scorecard.GradeAllOK)
+}
@@ -0,0 +1,44 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/younes-bami/kube-score/config"
+	ks "github.com/younes-bami/kube-score/domain"
+	"github.com/younes-bami/kube-score/scorecard"
+)
+
+func TestUnknownKindsMetaChecks(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore(config.Configuration{
+		AllFiles:          []ks.NamedReader{testFile("unknown-kinds.yaml")},
+		KubernetesVersion: config.Semver{Major: 1, Minor: 18},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, sc, 3)
+
+	for key, grade := range map[string]scorecard.Grade{
+		"Certificate/cert-manager.io/v1//example-com":      scorecard.GradeCritical,
+		"ServiceMonitor/monitoring.coreos.com/v1//example": scorecard.GradeAllOK,
+		"ConfigMap/v1//example":                            scorecard.GradeAllOK,
+	} {
+		o, ok := sc[key]
+		if !assert.True(t, ok, key) {
+			continue
+		}
+		var found bool
+		for _, c := range o.Checks {
+			if c.Check.ID == "label-values" {
+				found = true
+				assert.Equal(t, grade, c.Grade, key)
+			}
+		}
+		assert.True(t, found, key)
+	}
+
+	cert := sc["Certificate/cert-manager.io/v1//example-com"]
+	assert.Equal(t, "testdata/unknown-kinds.yaml", cert.FileLocation.Name)
+	assert.Equal(t, 1, cert.FileLocation.Line)
+}
@@ -33,6 +33,11 @@ func (so *ScoredObject) isEnabled(check ks.Check, annotations, childAnnotations
 		return true
 	}
 
+	// Optional checks that are enabled in the configuration
+	if _, ok := so.enabledOptionalTests[check.ID]; ok {
+		return true
+	}
+
 	// Optional checks are disabled unless explicitly allowed above
 	if check.Optional {
 		return false
@@ -34,6 +34,7 @@ func (s Scorecard) NewObject(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectM
 		useIgnoreChecksAnnotation:   cnf.UseIgnoreChecksAnnotation,
 		useOptionalChecksAnnotation: cnf.UseOptionalChecksAnnotation,
 		enabledOptionalTests:        cnf.EnabledOptionalTests,
+		severityOverrides:           cnf.SeverityOverrides,
 	}
 
 	// If this object already exists, return the previous version
@@ -63,6 +64,7 @@ type ScoredObject struct {
 	useIgnoreChecksAnnotation   bool
 	useOptionalChecksAnnotation bool
 	enabledOptionalTests        map[string]struct{}
+	severityOverrides           map[string]config.Severity
 }
 
 func (so *ScoredObject) AnyBelowOrEqualToGrade(threshold Grade) bool {
@@ -83,7 +85,10 @@ func (so *ScoredObject) HumanFriendlyRef() string {
 	if so.ObjectMeta.Namespace != "" {
 		s += "/" + so.ObjectMeta.Namespace
 	}
-	s += " " + so.TypeMeta.APIVersion + "/" + so.TypeMeta.Kind
+	// The type is unknown for documents that could not be parsed
+	if so.TypeMeta.APIVersion != "" || so.TypeMeta.Kind != "" {
+		s += " " + so.TypeMeta.APIVersion + "/" + so.TypeMeta.Kind
+	}
 	return s
 }
 
@@ -107,9 +112,40 @@ func (so *ScoredObject) Add(ts TestScore, check ks.Check, locationer ks.FileLoca
 		ts.Comments = []TestScoreComment{{Summary: fmt.Sprintf("Skipped because %s is ignored", check.ID)}}
 	}
 
+	if !ts.Skipped {
+		ts.Grade = so.overrideGrade(check, ts.Grade)
+	}
+
+	// Resolve the location of the fields that the comments refer to
+	if so.FileLocation.Fields != nil {
+		for i, comment := range ts.Comments {
+			if comment.FieldPath != "" {
+				ts.Comments[i].FileLocation = so.FileLocation.Fields.FieldLocation(comment.FieldPath)
+			}
+		}
+	}
+
 	so.Checks = append(so.Checks, ts)
 }
 
+// overrideGrade applies the configured severity of the check to failing grades
+func (so *ScoredObject) overrideGrade(check ks.Check, grade Grade) Grade {
+	severity, ok := so.severityOverrides[check.ID]
+	if !ok || grade > GradeWarning {
+		return grade
+	}
+
+	switch severity {
+	case config.SeverityCritical:
+		return GradeCritical
+	case config.SeverityWarning:
+		return GradeWarning
+	case config.SeverityInfo:
+		return GradeAlmostOK
+	}
+	return grade
+}
+
 type TestScore struct {
 	Check    ks.Check
 	Grade    Grade
@@ -144,6 +180,21 @@ type TestScoreComment struct {
 	Summary          string
 	Description      string
 	DocumentationURL string
+
+	// FieldPath is the path to the field that the comment refers to, such as "spec.containers[0].resources.limits"
+	FieldPath string
+
+	// FileLocation is the location of the field in FieldPath. It is only set if the location is known.
+	FileLocation ks.FileLocation
+}
+
+// Location returns the location of the field that the comment refers to if it is known,
+// or otherwise the location of the object
+func (c TestScoreComment) Location(object ks.FileLocation) ks.FileLocation {
+	if c.FileLocation.Line > 0 {
+		return c.FileLocation
+	}
+	return object
 }
 
 func (ts *TestScore) AddComment(path, summary, description string) {
@@ -162,3 +213,27 @@ func (ts *TestScore) AddCommentWithURL(path, summary, description, documentation
 		DocumentationURL: documentationURL,
 	})
 }
+
+// AddCommentWithFieldPath adds a comment that refers to a specific field in the object.
+// The field path is relative to the object, or to the pod template for pod checks.
+func (ts *TestScore) AddCommentWithFieldPath(path, fieldPath, summary, description string) {
+	ts.Comments = append(ts.Comments, TestScoreComment{
+		Path:        path,
+		FieldPath:   fieldPath,
+		Summary:     summary,
+		Description: description,
+	})
+}
+
+// PrefixFieldPaths prefixes the field paths of all comments, to make paths relative to a part of the object
+// (such as the pod template) relative to the whole object
+func (ts *TestScore) PrefixFieldPaths(prefix string) {
+	if prefix == "" {
+		return
+	}
+	for i, c := range ts.Comments {
+		if c.FieldPath != "" {
+			ts.Comments[i].FieldPath = prefix + "." + c.FieldPath
+		}
+	}
+}